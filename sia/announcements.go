@@ -0,0 +1,190 @@
+package sia
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// NetAddressChange a change of a host's announced netaddress
+	NetAddressChange struct {
+		NetAddress    string        `json:"net_address"`
+		TransactionID TransactionID `json:"transaction_id"`
+		Height        uint64        `json:"height"`
+		Timestamp     time.Time     `json:"timestamp"`
+	}
+
+	// HostAnnouncementHistory every announcement of a host in height order.
+	// Changes only includes announcements that changed the host's
+	// netaddress, starting with its first announcement.
+	HostAnnouncementHistory struct {
+		PublicKey      PublicKey          `json:"public_key"`
+		NetAddress     string             `json:"net_address"`
+		FirstHeight    uint64             `json:"first_height"`
+		LastHeight     uint64             `json:"last_height"`
+		FirstTimestamp time.Time          `json:"first_timestamp"`
+		LastTimestamp  time.Time          `json:"last_timestamp"`
+		Announcements  []Announcement     `json:"announcements"`
+		Changes        []NetAddressChange `json:"changes"`
+	}
+
+	// AnnouncementIndex indexes host announcements from blocks by public key
+	// and netaddress. Blocks can be added in any order and more than once.
+	AnnouncementIndex struct {
+		client *APIClient
+
+		mu sync.Mutex
+		// hosts maps the string form of a host's public key to its
+		// announcements
+		hosts map[string][]Announcement
+		// addresses maps a lowercase netaddress to the public keys of the
+		// hosts that announced it
+		addresses map[string]map[string]bool
+		seen      map[string]bool
+	}
+)
+
+// NewAnnouncementIndex creates a new empty announcement index. The client is
+// used to scan blocks from the Sia Central explorer.
+func NewAnnouncementIndex(client *APIClient) *AnnouncementIndex {
+	return &AnnouncementIndex{
+		client:    client,
+		hosts:     make(map[string][]Announcement),
+		addresses: make(map[string]map[string]bool),
+		seen:      make(map[string]bool),
+	}
+}
+
+// add adds the announcement to the index if it has not already been added.
+// The block is part of the key since the API may omit the transaction id.
+func (ai *AnnouncementIndex) add(ann Announcement) {
+	key := ann.PublicKey.String()
+	id := strings.Join([]string{ann.BlockID.String(), strconv.FormatUint(ann.Height, 10), ann.TransactionID.String(), key, ann.NetAddress}, "|")
+	if ai.seen[id] {
+		return
+	}
+	ai.seen[id] = true
+
+	ai.hosts[key] = append(ai.hosts[key], ann)
+
+	addr := strings.ToLower(ann.NetAddress)
+	if ai.addresses[addr] == nil {
+		ai.addresses[addr] = make(map[string]bool)
+	}
+	ai.addresses[addr][key] = true
+}
+
+// history builds the announcement history of a host
+func (ai *AnnouncementIndex) history(key string) (h HostAnnouncementHistory) {
+	announcements := append([]Announcement(nil), ai.hosts[key]...)
+	sort.SliceStable(announcements, func(i, j int) bool {
+		return announcements[i].Height < announcements[j].Height
+	})
+
+	first, last := announcements[0], announcements[len(announcements)-1]
+	h.PublicKey = first.PublicKey
+	h.NetAddress = last.NetAddress
+	h.FirstHeight = first.Height
+	h.FirstTimestamp = first.Timestamp
+	h.LastHeight = last.Height
+	h.LastTimestamp = last.Timestamp
+	h.Announcements = announcements
+
+	for i, ann := range announcements {
+		if i != 0 && ann.NetAddress == announcements[i-1].NetAddress {
+			continue
+		}
+
+		h.Changes = append(h.Changes, NetAddressChange{
+			NetAddress:    ann.NetAddress,
+			TransactionID: ann.TransactionID,
+			Height:        ann.Height,
+			Timestamp:     ann.Timestamp,
+		})
+	}
+
+	return
+}
+
+// AddBlocks adds the host announcements in the blocks to the index
+func (ai *AnnouncementIndex) AddBlocks(blocks ...Block) {
+	ai.mu.Lock()
+	defer ai.mu.Unlock()
+
+	for _, b := range blocks {
+		for _, ann := range b.announcements() {
+			// fill in the block details if the API omitted them
+			if ann.BlockID.IsZero() {
+				ann.BlockID = b.ID
+			}
+			if ann.Height == 0 {
+				ann.Height = b.Height
+			}
+			if ann.Timestamp.IsZero() {
+				ann.Timestamp = b.Timestamp
+			}
+
+			ai.add(ann)
+		}
+	}
+}
+
+// Scan adds the host announcements in the blocks from start to end inclusive
+// to the index
+func (ai *AnnouncementIndex) Scan(start, end uint64) error {
+	return ai.client.IterateBlocks(start, end, func(b Block) error {
+		ai.AddBlocks(b)
+		return nil
+	})
+}
+
+// Host returns the announcement history of the host with the public key
+func (ai *AnnouncementIndex) Host(pk PublicKey) (HostAnnouncementHistory, bool) {
+	ai.mu.Lock()
+	defer ai.mu.Unlock()
+
+	key := pk.String()
+	if len(ai.hosts[key]) == 0 {
+		return HostAnnouncementHistory{}, false
+	}
+
+	return ai.history(key), true
+}
+
+// HostsByNetAddress returns the announcement histories of every host that has
+// announced the netaddress. Netaddresses are compared case-insensitively.
+func (ai *AnnouncementIndex) HostsByNetAddress(netaddress string) (hosts []HostAnnouncementHistory) {
+	ai.mu.Lock()
+	defer ai.mu.Unlock()
+
+	for key := range ai.addresses[strings.ToLower(netaddress)] {
+		hosts = append(hosts, ai.history(key))
+	}
+
+	sort.Slice(hosts, func(i, j int) bool {
+		return hosts[i].FirstHeight < hosts[j].FirstHeight
+	})
+	return
+}
+
+// Hosts returns the announcement histories of every indexed host sorted by
+// first announcement height
+func (ai *AnnouncementIndex) Hosts() (hosts []HostAnnouncementHistory) {
+	ai.mu.Lock()
+	defer ai.mu.Unlock()
+
+	for key := range ai.hosts {
+		hosts = append(hosts, ai.history(key))
+	}
+
+	sort.Slice(hosts, func(i, j int) bool {
+		if hosts[i].FirstHeight != hosts[j].FirstHeight {
+			return hosts[i].FirstHeight < hosts[j].FirstHeight
+		}
+		return hosts[i].PublicKey.String() < hosts[j].PublicKey.String()
+	})
+	return
+}
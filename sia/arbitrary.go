@@ -0,0 +1,215 @@
+package sia
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gitlab.com/NebulousLabs/encoding"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// ArbitraryDataHostAnnouncement the data is a host announcement
+	ArbitraryDataHostAnnouncement ArbitraryDataType = "host_announcement"
+	// ArbitraryDataContractIdentifier the data is a renter's signed contract
+	// identifier, used to recover contracts from a seed
+	ArbitraryDataContractIdentifier ArbitraryDataType = "contract_identifier"
+	// ArbitraryDataFoundationUpdate the data updates the Foundation's
+	// addresses
+	ArbitraryDataFoundationUpdate ArbitraryDataType = "foundation_update"
+	// ArbitraryDataNonSia the data is prefixed as not being part of the Sia
+	// protocol
+	ArbitraryDataNonSia ArbitraryDataType = "non_sia"
+	// ArbitraryDataUnknown the data does not have a recognized prefix
+	ArbitraryDataUnknown ArbitraryDataType = "unknown"
+
+	// contractIdentifierSize the size of a signed contract identifier
+	// including its prefix
+	contractIdentifierSize = 80
+)
+
+type (
+	// ArbitraryDataType the type of data decoded from a transaction's
+	// arbitrary data
+	ArbitraryDataType string
+
+	// DecodedAnnouncement a host announcement decoded from arbitrary data.
+	// Verified is true if the announcement is signed by the announced public
+	// key.
+	DecodedAnnouncement struct {
+		NetAddress string    `json:"net_address"`
+		PublicKey  PublicKey `json:"public_key"`
+		Signature  []byte    `json:"signature"`
+		Verified   bool      `json:"verified"`
+		Error      string    `json:"error,omitempty"`
+	}
+
+	// ContractIdentifier a renter's signed contract identifier. The host key
+	// is encrypted with the renter's seed.
+	ContractIdentifier struct {
+		Identifier       []byte `json:"identifier"`
+		Signature        []byte `json:"signature"`
+		EncryptedHostKey []byte `json:"encrypted_host_key"`
+	}
+
+	// FoundationUpdate an update of the Foundation's primary and failsafe
+	// addresses
+	FoundationUpdate struct {
+		NewPrimary  UnlockHash `json:"new_primary"`
+		NewFailsafe UnlockHash `json:"new_failsafe"`
+	}
+
+	// ArbitraryData decoded arbitrary data. Only the field matching the type is
+	// set.
+	ArbitraryData struct {
+		Type               ArbitraryDataType    `json:"type"`
+		Specifier          string               `json:"specifier"`
+		Data               []byte               `json:"data"`
+		Announcement       *DecodedAnnouncement `json:"announcement,omitempty"`
+		ContractIdentifier *ContractIdentifier  `json:"contract_identifier,omitempty"`
+		FoundationUpdate   *FoundationUpdate    `json:"foundation_update,omitempty"`
+		Error              string               `json:"error,omitempty"`
+	}
+)
+
+// decodeAnnouncement decodes a host announcement and verifies its signature.
+// The fields of the announcement are returned even if the signature is
+// invalid so forged announcements can be inspected.
+func decodeAnnouncement(data []byte) (ann DecodedAnnouncement, err error) {
+	var ha modules.HostAnnouncement
+	var sig crypto.Signature
+
+	dec := encoding.NewDecoder(bytes.NewReader(data), len(data)*3)
+	if err = dec.Decode(&ha); err != nil {
+		err = fmt.Errorf("unable to decode announcement: %w", err)
+		return
+	}
+
+	ann.NetAddress = string(ha.NetAddress)
+	ann.PublicKey = PublicKey(ha.PublicKey)
+
+	if err = dec.Decode(&sig); err != nil {
+		ann.Error = "missing signature"
+		return ann, nil
+	}
+	ann.Signature = sig[:]
+
+	if _, _, verifyErr := modules.DecodeAnnouncement(data); verifyErr != nil {
+		ann.Error = verifyErr.Error()
+		return ann, nil
+	}
+
+	ann.Verified = true
+	return
+}
+
+// DecodeArbitraryData decodes arbitrary data using its specifier prefix
+func DecodeArbitraryData(data []byte) (decoded ArbitraryData) {
+	decoded.Type = ArbitraryDataUnknown
+	decoded.Data = data
+
+	if len(data) < types.SpecifierLen {
+		return
+	}
+
+	var prefix types.Specifier
+	copy(prefix[:], data)
+	decoded.Specifier = strings.TrimRight(string(prefix[:]), "\x00")
+
+	switch prefix {
+	case modules.PrefixHostAnnouncement:
+		ann, err := decodeAnnouncement(data)
+		if err != nil {
+			decoded.Error = err.Error()
+			return
+		}
+
+		decoded.Type = ArbitraryDataHostAnnouncement
+		decoded.Announcement = &ann
+	case modules.PrefixFileContractIdentifier:
+		decoded.Type = ArbitraryDataContractIdentifier
+		decoded.ContractIdentifier = decodeContractIdentifier(data)
+	case types.SpecifierFoundation:
+		var update types.FoundationUnlockHashUpdate
+		if err := encoding.Unmarshal(data[types.SpecifierLen:], &update); err != nil {
+			decoded.Error = fmt.Sprintf("unable to decode foundation update: %s", err)
+			return
+		}
+
+		decoded.Type = ArbitraryDataFoundationUpdate
+		decoded.FoundationUpdate = &FoundationUpdate{
+			NewPrimary:  UnlockHash(update.NewPrimary),
+			NewFailsafe: UnlockHash(update.NewFailsafe),
+		}
+	case modules.PrefixNonSia:
+		decoded.Type = ArbitraryDataNonSia
+	}
+
+	return
+}
+
+// decodeContractIdentifier splits a signed contract identifier into its parts.
+// nil is returned if the data is too short.
+func decodeContractIdentifier(data []byte) *ContractIdentifier {
+	if len(data) < contractIdentifierSize {
+		return nil
+	}
+
+	return &ContractIdentifier{
+		Identifier:       data[16:48],
+		Signature:        data[48:80],
+		EncryptedHostKey: data[80:],
+	}
+}
+
+// DecodeArbitraryData decodes each of the transaction's arbitrary data.
+// Renters prefix contract identifiers with the non-Sia specifier, so non-Sia
+// data in a contract formation transaction is decoded as a contract
+// identifier.
+func (t Transaction) DecodeArbitraryData() (decoded []ArbitraryData) {
+	for _, data := range t.ArbitraryData {
+		d := DecodeArbitraryData(data)
+		if d.Type == ArbitraryDataNonSia && len(t.StorageContracts) != 0 {
+			if ci := decodeContractIdentifier(data); ci != nil {
+				d.Type = ArbitraryDataContractIdentifier
+				d.ContractIdentifier = ci
+			}
+		}
+
+		decoded = append(decoded, d)
+	}
+
+	return
+}
+
+// VerifyAnnouncements checks that every host announcement reported for the
+// transaction is present in its arbitrary data and signed by the announced
+// public key
+func VerifyAnnouncements(t Transaction) error {
+	verified := make(map[string]bool)
+	for _, d := range t.DecodeArbitraryData() {
+		if d.Type != ArbitraryDataHostAnnouncement {
+			continue
+		} else if !d.Announcement.Verified {
+			return fmt.Errorf("announcement of %s for %s has an invalid signature: %s", d.Announcement.NetAddress, d.Announcement.PublicKey, d.Announcement.Error)
+		}
+
+		verified[d.Announcement.PublicKey.String()+"@"+d.Announcement.NetAddress] = true
+	}
+
+	for _, ann := range t.HostAnnouncements {
+		if !verified[ann.PublicKey.String()+"@"+ann.NetAddress] {
+			return fmt.Errorf("announcement of %s for %s not found in arbitrary data", ann.NetAddress, ann.PublicKey)
+		}
+	}
+
+	if len(t.HostAnnouncements) == 0 && len(verified) == 0 {
+		return errors.New("transaction does not contain any announcements")
+	}
+
+	return nil
+}
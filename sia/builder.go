@@ -0,0 +1,192 @@
+package sia
+
+import (
+	"errors"
+	"fmt"
+
+	"go.sia.tech/siad/types"
+)
+
+type (
+	// BuildOptions the parameters of a transaction to build. Outputs are the
+	// candidate outputs to spend, usually the unspent outputs returned by
+	// FindAddressBalance, and UnlockConditions must include the unlock
+	// conditions of every address that may be spent from.
+	BuildOptions struct {
+		Outputs          []SiacoinOutput
+		UnlockConditions []types.UnlockConditions
+		Recipients       []types.SiacoinOutput
+		ChangeAddress    UnlockHash
+
+		// Height is the current block height, outputs that have not matured
+		// at the height are not spent
+		Height uint64
+		// UnconfirmedTransactions are transactions that have not been
+		// confirmed yet, outputs they spend are not spent again
+		UnconfirmedTransactions []Transaction
+		// Selector selects the outputs to spend, largest first if nil
+		Selector CoinSelector
+		// FeePerByte is the miner fee per byte of the signed transaction
+		FeePerByte types.Currency
+
+		// APIFee and APIFeeAddress add an output paying the Sia Central API
+		// fee if APIFee is not zero
+		APIFee        types.Currency
+		APIFeeAddress UnlockHash
+	}
+
+	// UnsignedTransaction a transaction ready to be signed. Inputs are the
+	// outputs spent by the transaction in the same order as its siacoin
+	// inputs.
+	UnsignedTransaction struct {
+		Transaction types.Transaction `json:"transaction"`
+		Inputs      []SiacoinOutput   `json:"inputs"`
+		Fee         types.Currency    `json:"fee"`
+		APIFee      types.Currency    `json:"api_fee"`
+		Change      types.Currency    `json:"change"`
+		Size        int               `json:"size"`
+	}
+)
+
+var (
+	// ErrInsufficientFunds is returned when the available outputs cannot
+	// cover the amount being sent and its fees
+	ErrInsufficientFunds = errors.New("insufficient funds")
+
+	// maxChangePlaceholder is larger than the total supply of siacoins
+	maxChangePlaceholder = types.SiacoinPrecision.Mul64(1e12)
+)
+
+// buildTransaction returns the transaction spending the inputs to the
+// recipients and the API fee, along with the miner fee for its estimated
+// signed size. If withChange is true a change output is added as the last
+// output with a placeholder value larger than any real change, so the fee is
+// never underestimated.
+func buildTransaction(opts BuildOptions, inputs []SiacoinOutput, ucs map[UnlockHash]types.UnlockConditions, withChange bool) (txn types.Transaction, fee types.Currency) {
+	for _, o := range inputs {
+		txn.SiacoinInputs = append(txn.SiacoinInputs, types.SiacoinInput{
+			ParentID:         o.OutputID.SiacoinOutputID(),
+			UnlockConditions: ucs[o.UnlockHash],
+		})
+	}
+
+	txn.SiacoinOutputs = append(txn.SiacoinOutputs, opts.Recipients...)
+	if !opts.APIFee.IsZero() {
+		txn.SiacoinOutputs = append(txn.SiacoinOutputs, types.SiacoinOutput{
+			Value:      opts.APIFee,
+			UnlockHash: opts.APIFeeAddress.Siad(),
+		})
+	}
+
+	if withChange {
+		txn.SiacoinOutputs = append(txn.SiacoinOutputs, types.SiacoinOutput{
+			Value:      maxChangePlaceholder,
+			UnlockHash: opts.ChangeAddress.Siad(),
+		})
+	}
+
+	fee = opts.FeePerByte.Mul64(uint64(EstimateSignedSize(txn)))
+	return
+}
+
+// BuildTransaction selects outputs to fund the recipients, the API fee and the
+// miner fee using the options' coin selector and returns the unsigned
+// transaction. Any excess is sent to the change address.
+func BuildTransaction(opts BuildOptions) (utxn UnsignedTransaction, err error) {
+	if len(opts.Recipients) == 0 {
+		err = errors.New("transaction must have at least one recipient")
+		return
+	} else if opts.ChangeAddress.IsZero() {
+		err = errors.New("change address must be set")
+		return
+	} else if !opts.APIFee.IsZero() && opts.APIFeeAddress.IsZero() {
+		err = errors.New("api fee address must be set")
+		return
+	}
+
+	ucs := make(map[UnlockHash]types.UnlockConditions, len(opts.UnlockConditions))
+	for _, uc := range opts.UnlockConditions {
+		ucs[UnlockHash(uc.UnlockHash())] = uc
+	}
+
+	target := opts.APIFee
+	for _, o := range opts.Recipients {
+		if o.Value.IsZero() {
+			err = errors.New("recipient outputs must not be zero")
+			return
+		}
+		target = target.Add(o.Value)
+	}
+
+	var candidates []SiacoinOutput
+	for _, o := range spendableOutputs(opts.Outputs, opts.Height, opts.UnconfirmedTransactions) {
+		if _, ok := ucs[o.UnlockHash]; ok {
+			candidates = append(candidates, o)
+		}
+	}
+
+	selector := opts.Selector
+	if selector == nil {
+		selector = LargestFirstSelector{}
+	}
+
+	fee := func(inputs []SiacoinOutput, change bool) types.Currency {
+		_, fee := buildTransaction(opts, inputs, ucs, change)
+		return fee
+	}
+
+	selected, err := selector.SelectCoins(candidates, target, fee)
+	if errors.Is(err, ErrInsufficientFunds) {
+		err = fmt.Errorf("%w: %s available, at least %s required", ErrInsufficientFunds, sumOutputs(candidates).HumanString(), target.HumanString())
+		return
+	} else if err != nil {
+		return
+	}
+
+	funded := sumOutputs(selected)
+	noChangeFee, changeFee := fee(selected, false), fee(selected, true)
+
+	utxn.Inputs = selected
+	utxn.APIFee = opts.APIFee
+	switch {
+	case funded.Cmp(target.Add(noChangeFee)) < 0:
+		err = fmt.Errorf("%w: selected outputs total %s, at least %s required", ErrInsufficientFunds, funded.HumanString(), target.Add(noChangeFee).HumanString())
+		return
+	case funded.Cmp(target.Add(changeFee)) > 0:
+		utxn.Transaction, utxn.Fee = buildTransaction(opts, selected, ucs, true)
+		utxn.Change = funded.Sub(target).Sub(changeFee)
+		utxn.Transaction.SiacoinOutputs[len(utxn.Transaction.SiacoinOutputs)-1].Value = utxn.Change
+	default:
+		// the excess is smaller than the cost of a change output, so it is
+		// added to the miner fee
+		utxn.Transaction, _ = buildTransaction(opts, selected, ucs, false)
+		utxn.Fee = funded.Sub(target)
+	}
+
+	utxn.Transaction.MinerFees = []types.Currency{utxn.Fee}
+	utxn.Size = EstimateSignedSize(utxn.Transaction)
+	return
+}
+
+// BuildTransaction builds an unsigned transaction using the network's maximum
+// recommended fee per byte if opts.FeePerByte is zero. If includeAPIFee is
+// true the Sia Central API fee is added to the transaction.
+func (a *APIClient) BuildTransaction(opts BuildOptions, includeAPIFee bool) (utxn UnsignedTransaction, err error) {
+	if opts.FeePerByte.IsZero() {
+		_, opts.FeePerByte, err = a.GetTransactionFees()
+		if err != nil {
+			err = fmt.Errorf("unable to get transaction fees: %w", err)
+			return
+		}
+	}
+
+	if includeAPIFee {
+		opts.APIFee, opts.APIFeeAddress, err = a.GetAPIFees()
+		if err != nil {
+			err = fmt.Errorf("unable to get api fees: %w", err)
+			return
+		}
+	}
+
+	return BuildTransaction(opts)
+}
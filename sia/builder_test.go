@@ -0,0 +1,171 @@
+package sia
+
+import (
+	"errors"
+	"testing"
+
+	"gitlab.com/NebulousLabs/fastrand"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+func TestBuildTransaction(t *testing.T) {
+	const height = 300000
+
+	var seed modules.Seed
+	fastrand.Read(seed[:])
+	ks := NewKeystore()
+	addr := ks.AddSeed(seed, 1)[0]
+	ucs := ks.UnlockConditions()
+
+	sc := func(n uint64) types.Currency { return types.SiacoinPrecision.Mul64(n) }
+	output := func(id byte, value types.Currency, maturity uint64) SiacoinOutput {
+		return SiacoinOutput{OutputID: OutputID{id}, UnlockHash: addr, Value: value, MaturityHeight: maturity}
+	}
+
+	rate := types.SiacoinPrecision.Div64(1e6)
+	recipient := types.SiacoinOutput{Value: sc(30), UnlockHash: types.UnlockHash{9}}
+
+	// the fee of a transaction spending a single input without change
+	probe := BuildOptions{Recipients: []types.SiacoinOutput{recipient}, FeePerByte: rate}
+	_, noChangeFee := buildTransaction(probe, []SiacoinOutput{output(0, types.ZeroCurrency, 0)}, map[UnlockHash]types.UnlockConditions{addr: ucs[0]}, false)
+
+	tests := []struct {
+		name        string
+		outputs     []SiacoinOutput
+		unconfirmed []Transaction
+		apiFee      types.Currency
+		inputs      int
+		change      bool
+		// fee is checked if it is not zero
+		fee types.Currency
+		err error
+	}{
+		{
+			name:    "change",
+			outputs: []SiacoinOutput{output(1, sc(100), 0)},
+			inputs:  1,
+			change:  true,
+		},
+		{
+			name:    "exact without change",
+			outputs: []SiacoinOutput{output(1, sc(30).Add(noChangeFee), 0)},
+			inputs:  1,
+			fee:     noChangeFee,
+		},
+		{
+			name:    "excess below change cost added to fee",
+			outputs: []SiacoinOutput{output(1, sc(30).Add(noChangeFee).Add64(1), 0)},
+			inputs:  1,
+			fee:     noChangeFee.Add64(1),
+		},
+		{
+			name:    "api fee",
+			outputs: []SiacoinOutput{output(1, sc(100), 0)},
+			apiFee:  sc(1),
+			inputs:  1,
+			change:  true,
+		},
+		{
+			name:    "multiple inputs",
+			outputs: []SiacoinOutput{output(1, sc(20), 0), output(2, sc(20), 0)},
+			inputs:  2,
+			change:  true,
+		},
+		{
+			name:    "immature outputs are not spent",
+			outputs: []SiacoinOutput{output(1, sc(100), height+1), output(2, sc(20), 0)},
+			err:     ErrInsufficientFunds,
+		},
+		{
+			name:    "outputs spent by unconfirmed transactions are not spent",
+			outputs: []SiacoinOutput{output(1, sc(100), 0), output(2, sc(20), 0)},
+			unconfirmed: []Transaction{{
+				SiacoinInputs: []SiacoinInput{{SiacoinOutput: output(1, sc(100), 0)}},
+			}},
+			err: ErrInsufficientFunds,
+		},
+		{
+			name:    "outputs of unknown addresses are not spent",
+			outputs: []SiacoinOutput{{OutputID: OutputID{1}, UnlockHash: UnlockHash{1}, Value: sc(100)}},
+			err:     ErrInsufficientFunds,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utxn, err := BuildTransaction(BuildOptions{
+				Outputs:                 tt.outputs,
+				UnlockConditions:        ucs,
+				Recipients:              []types.SiacoinOutput{recipient},
+				ChangeAddress:           addr,
+				Height:                  height,
+				UnconfirmedTransactions: tt.unconfirmed,
+				FeePerByte:              rate,
+				APIFee:                  tt.apiFee,
+				APIFeeAddress:           UnlockHash{7},
+			})
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected %v, got %v", tt.err, err)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(utxn.Inputs) != tt.inputs {
+				t.Fatalf("expected %d inputs, got %d", tt.inputs, len(utxn.Inputs))
+			} else if utxn.Change.IsZero() == tt.change {
+				t.Fatalf("expected change %v, got %s", tt.change, utxn.Change)
+			} else if !tt.fee.IsZero() && !utxn.Fee.Equals(tt.fee) {
+				t.Fatalf("expected fee %s, got %s", tt.fee, utxn.Fee)
+			}
+
+			txn := utxn.Transaction
+			outputs := txn.MinerFees[0]
+			for _, o := range txn.SiacoinOutputs {
+				outputs = outputs.Add(o.Value)
+			}
+			if inputs := sumOutputs(utxn.Inputs); !inputs.Equals(outputs) {
+				t.Fatalf("inputs %s do not equal outputs plus fee %s", inputs, outputs)
+			} else if !txn.MinerFees[0].Equals(utxn.Fee) {
+				t.Fatalf("miner fee %s does not match fee %s", txn.MinerFees[0], utxn.Fee)
+			}
+
+			if err := ks.SignTransaction(&txn, height); err != nil {
+				t.Fatal(err)
+			} else if err := txn.StandaloneValid(height); err != nil {
+				t.Fatalf("signed transaction is invalid: %s", err)
+			} else if size := txn.MarshalSiaSize(); size > utxn.Size {
+				t.Fatalf("signed size %d exceeds estimated size %d", size, utxn.Size)
+			} else if minFee := rate.Mul64(uint64(txn.MarshalSiaSize())); utxn.Fee.Cmp(minFee) < 0 {
+				t.Fatalf("fee %s is below the fee for the signed size %s", utxn.Fee, minFee)
+			}
+		})
+	}
+}
+
+func TestBuildTransactionOptions(t *testing.T) {
+	recipients := []types.SiacoinOutput{{Value: types.SiacoinPrecision, UnlockHash: types.UnlockHash{9}}}
+
+	tests := []struct {
+		name string
+		opts BuildOptions
+	}{
+		{"no recipients", BuildOptions{ChangeAddress: UnlockHash{1}}},
+		{"no change address", BuildOptions{Recipients: recipients}},
+		{"no api fee address", BuildOptions{Recipients: recipients, ChangeAddress: UnlockHash{1}, APIFee: types.SiacoinPrecision}},
+		{"zero recipient", BuildOptions{Recipients: []types.SiacoinOutput{{UnlockHash: types.UnlockHash{9}}}, ChangeAddress: UnlockHash{1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := BuildTransaction(tt.opts); err == nil {
+				t.Fatal("expected invalid options to be rejected")
+			} else if errors.Is(err, ErrInsufficientFunds) {
+				t.Fatalf("expected an options error, got %s", err)
+			}
+		})
+	}
+}
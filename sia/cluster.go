@@ -0,0 +1,208 @@
+package sia
+
+import (
+	"sort"
+	"sync"
+)
+
+type (
+	// AddressClusterer groups addresses that are likely controlled by the
+	// same wallet using the common-input-ownership heuristic: every address
+	// spent from in the same transaction is assumed to belong to the same
+	// owner. Transactions forming contracts are ignored since both the renter
+	// and the host fund them.
+	AddressClusterer struct {
+		mu        sync.Mutex
+		parents   map[UnlockHash]UnlockHash
+		sizes     map[UnlockHash]int
+		processed map[TransactionID]bool
+	}
+)
+
+// NewAddressClusterer creates a new empty address clusterer
+func NewAddressClusterer() *AddressClusterer {
+	return &AddressClusterer{
+		parents:   make(map[UnlockHash]UnlockHash),
+		sizes:     make(map[UnlockHash]int),
+		processed: make(map[TransactionID]bool),
+	}
+}
+
+// find returns the root of the address's cluster, compressing the path to it
+func (ac *AddressClusterer) find(addr UnlockHash) UnlockHash {
+	root := addr
+	for {
+		parent, exists := ac.parents[root]
+		if !exists || parent == root {
+			break
+		}
+		root = parent
+	}
+
+	for addr != root {
+		next := ac.parents[addr]
+		ac.parents[addr] = root
+		addr = next
+	}
+
+	return root
+}
+
+// add adds the address to the clusterer as its own cluster if it is not
+// already tracked
+func (ac *AddressClusterer) add(addr UnlockHash) {
+	if _, exists := ac.parents[addr]; exists {
+		return
+	}
+
+	ac.parents[addr] = addr
+	ac.sizes[addr] = 1
+}
+
+// union merges the clusters of the two addresses
+func (ac *AddressClusterer) union(a, b UnlockHash) {
+	rootA, rootB := ac.find(a), ac.find(b)
+	if rootA == rootB {
+		return
+	}
+
+	if ac.sizes[rootA] < ac.sizes[rootB] {
+		rootA, rootB = rootB, rootA
+	}
+
+	ac.parents[rootB] = rootA
+	ac.sizes[rootA] += ac.sizes[rootB]
+	delete(ac.sizes, rootB)
+}
+
+// addTransaction unions the input addresses of the transaction
+func (ac *AddressClusterer) addTransaction(t Transaction) {
+	if ac.processed[t.ID] {
+		return
+	} else if len(t.StorageContracts) != 0 {
+		return
+	}
+
+	ac.processed[t.ID] = true
+	if len(t.SiacoinInputs) == 0 {
+		return
+	}
+
+	first := t.SiacoinInputs[0].UnlockHash
+	ac.add(first)
+	for _, sci := range t.SiacoinInputs[1:] {
+		ac.add(sci.UnlockHash)
+		ac.union(first, sci.UnlockHash)
+	}
+}
+
+// AddTransactions adds the transactions' inputs to the clusters. Transactions
+// that were already added are ignored, so overlapping address histories can
+// be added safely.
+func (ac *AddressClusterer) AddTransactions(transactions ...Transaction) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	for _, t := range transactions {
+		ac.addTransaction(t)
+	}
+}
+
+// AddBlocks adds the inputs of each of the blocks' transactions to the
+// clusters
+func (ac *AddressClusterer) AddBlocks(blocks ...Block) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	for _, b := range blocks {
+		for _, t := range b.Transactions {
+			ac.addTransaction(t)
+		}
+	}
+}
+
+// SameCluster returns true if both addresses are in the same cluster
+func (ac *AddressClusterer) SameCluster(a, b UnlockHash) bool {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	if a == b {
+		return true
+	}
+
+	_, existsA := ac.parents[a]
+	_, existsB := ac.parents[b]
+	return existsA && existsB && ac.find(a) == ac.find(b)
+}
+
+// ClusterSize returns the number of addresses in the address's cluster. Zero
+// is returned if the address has not been spent from.
+func (ac *AddressClusterer) ClusterSize(addr UnlockHash) int {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	if _, exists := ac.parents[addr]; !exists {
+		return 0
+	}
+
+	return ac.sizes[ac.find(addr)]
+}
+
+// Cluster returns the addresses in the same cluster as the address, including
+// the address itself. nil is returned if the address has not been spent from.
+func (ac *AddressClusterer) Cluster(addr UnlockHash) (cluster []UnlockHash) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	if _, exists := ac.parents[addr]; !exists {
+		return nil
+	}
+
+	root := ac.find(addr)
+	for member := range ac.parents {
+		if ac.find(member) == root {
+			cluster = append(cluster, member)
+		}
+	}
+
+	sortUnlockHashes(cluster)
+	return
+}
+
+// Clusters returns every cluster with at least minSize addresses, largest
+// first
+func (ac *AddressClusterer) Clusters(minSize int) (clusters [][]UnlockHash) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	members := make(map[UnlockHash][]UnlockHash)
+	for addr := range ac.parents {
+		root := ac.find(addr)
+		if ac.sizes[root] < minSize {
+			continue
+		}
+
+		members[root] = append(members[root], addr)
+	}
+
+	for _, cluster := range members {
+		sortUnlockHashes(cluster)
+		clusters = append(clusters, cluster)
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i]) != len(clusters[j]) {
+			return len(clusters[i]) > len(clusters[j])
+		}
+		return clusters[i][0].String() < clusters[j][0].String()
+	})
+
+	return
+}
+
+// sortUnlockHashes sorts the addresses by their string representation
+func sortUnlockHashes(addresses []UnlockHash) {
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].String() < addresses[j].String()
+	})
+}
@@ -0,0 +1,58 @@
+package sia
+
+import "testing"
+
+func TestAddressClusterer(t *testing.T) {
+	a, b, c, d, e, f := UnlockHash{1}, UnlockHash{2}, UnlockHash{3}, UnlockHash{4}, UnlockHash{5}, UnlockHash{6}
+	txn := func(id byte, addrs ...UnlockHash) Transaction {
+		t := Transaction{ID: TransactionID{id}}
+		for _, addr := range addrs {
+			t.SiacoinInputs = append(t.SiacoinInputs, SiacoinInput{SiacoinOutput: SiacoinOutput{UnlockHash: addr}})
+		}
+		return t
+	}
+	formation := txn(5, e, f)
+	formation.StorageContracts = []StorageContract{{}}
+
+	ac := NewAddressClusterer()
+	ac.AddTransactions(txn(1, a, b), txn(2, c), txn(1, a, b), formation)
+	// b and c are joined by a transaction in a block, merging both clusters
+	ac.AddBlocks(Block{Transactions: []Transaction{txn(3, b, c), txn(4)}})
+
+	tests := []struct {
+		name  string
+		a, b  UnlockHash
+		same  bool
+		sizeA int
+	}{
+		{"direct", a, b, true, 3},
+		{"transitive", a, c, true, 3},
+		{"same address", d, d, true, 0},
+		{"unspent address", a, d, false, 3},
+		{"contract formation", e, f, false, 0},
+		{"contract formation renter", a, e, false, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ac.SameCluster(tt.a, tt.b) != tt.same {
+				t.Fatalf("expected same cluster %v", tt.same)
+			} else if size := ac.ClusterSize(tt.a); size != tt.sizeA {
+				t.Fatalf("expected cluster size %v, got %v", tt.sizeA, size)
+			}
+		})
+	}
+
+	if cluster := ac.Cluster(c); len(cluster) != 3 {
+		t.Fatalf("expected 3 addresses, got %v", cluster)
+	} else if ac.Cluster(e) != nil {
+		t.Fatal("expected no cluster for contract formation inputs")
+	}
+
+	ac.AddTransactions(txn(6, d))
+	if clusters := ac.Clusters(1); len(clusters) != 2 || len(clusters[0]) != 3 || len(clusters[1]) != 1 {
+		t.Fatalf("unexpected clusters %v", clusters)
+	} else if clusters := ac.Clusters(2); len(clusters) != 1 || len(clusters[0]) != 3 {
+		t.Fatalf("unexpected clusters %v", clusters)
+	}
+}
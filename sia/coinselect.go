@@ -0,0 +1,237 @@
+package sia
+
+import (
+	"sort"
+
+	"gitlab.com/NebulousLabs/fastrand"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// bnbMaxTries is the maximum number of selections the branch and bound
+	// selector explores before falling back
+	bnbMaxTries = 100000
+)
+
+type (
+	// FeeFunc returns the miner fee of a transaction spending the inputs, with
+	// or without a change output
+	FeeFunc func(inputs []SiacoinOutput, change bool) types.Currency
+
+	// CoinSelector selects outputs to fund a transaction. The selected outputs
+	// must cover the target plus the fee of a transaction spending them
+	// without change. Any excess larger than the cost of a change output is
+	// sent to change, smaller excess is added to the miner fee.
+	CoinSelector interface {
+		SelectCoins(candidates []SiacoinOutput, target types.Currency, fee FeeFunc) ([]SiacoinOutput, error)
+	}
+
+	// LargestFirstSelector spends the largest outputs first, minimizing the
+	// number of inputs and the fee
+	LargestFirstSelector struct{}
+
+	// SmallestFirstSelector spends the smallest outputs first, consolidating
+	// dust at the cost of higher fees
+	SmallestFirstSelector struct{}
+
+	// BranchAndBoundSelector searches for a set of outputs that funds the
+	// transaction without a change output. If no such set is found, the
+	// fallback selector is used, or largest first if it is nil.
+	BranchAndBoundSelector struct {
+		Fallback CoinSelector
+	}
+
+	// RandomSelector spends outputs in random order so the selection does
+	// not reveal the structure of the wallet
+	RandomSelector struct{}
+
+	// PrivacySelector minimizes the number of addresses linked by the
+	// transaction. Outputs from a single address are preferred; otherwise
+	// whole addresses are combined, fewest first.
+	PrivacySelector struct{}
+)
+
+// sumOutputs returns the total value of the outputs
+func sumOutputs(outputs []SiacoinOutput) (sum types.Currency) {
+	for _, o := range outputs {
+		sum = sum.Add(o.Value)
+	}
+	return
+}
+
+// covers returns true if the outputs fund the target and the fee of a
+// transaction without change
+func covers(selected []SiacoinOutput, target types.Currency, fee FeeFunc) bool {
+	return sumOutputs(selected).Cmp(target.Add(fee(selected, false))) >= 0
+}
+
+// accumulate selects outputs in order until they cover the target
+func accumulate(ordered []SiacoinOutput, target types.Currency, fee FeeFunc) ([]SiacoinOutput, error) {
+	var selected []SiacoinOutput
+	for _, o := range ordered {
+		selected = append(selected, o)
+		if covers(selected, target, fee) {
+			return selected, nil
+		}
+	}
+
+	return nil, ErrInsufficientFunds
+}
+
+// sortByValue returns a copy of the outputs sorted by value, descending if
+// desc is true
+func sortByValue(outputs []SiacoinOutput, desc bool) []SiacoinOutput {
+	sorted := append([]SiacoinOutput(nil), outputs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if desc {
+			return sorted[i].Value.Cmp(sorted[j].Value) > 0
+		}
+		return sorted[i].Value.Cmp(sorted[j].Value) < 0
+	})
+	return sorted
+}
+
+// spendableOutputs returns the outputs that have matured at the height and
+// are not spent by a confirmed or unconfirmed transaction
+func spendableOutputs(outputs []SiacoinOutput, height uint64, unconfirmed []Transaction) (spendable []SiacoinOutput) {
+	spent := make(map[OutputID]bool)
+	for _, t := range unconfirmed {
+		for _, sci := range t.SiacoinInputs {
+			spent[sci.OutputID] = true
+		}
+	}
+
+	for _, o := range outputs {
+		if o.MaturityHeight > height || !o.SpentTransactionID.IsZero() || spent[o.OutputID] {
+			continue
+		}
+		spendable = append(spendable, o)
+	}
+	return
+}
+
+// SelectCoins implements CoinSelector
+func (LargestFirstSelector) SelectCoins(candidates []SiacoinOutput, target types.Currency, fee FeeFunc) ([]SiacoinOutput, error) {
+	return accumulate(sortByValue(candidates, true), target, fee)
+}
+
+// SelectCoins implements CoinSelector
+func (SmallestFirstSelector) SelectCoins(candidates []SiacoinOutput, target types.Currency, fee FeeFunc) ([]SiacoinOutput, error) {
+	return accumulate(sortByValue(candidates, false), target, fee)
+}
+
+// SelectCoins implements CoinSelector
+func (RandomSelector) SelectCoins(candidates []SiacoinOutput, target types.Currency, fee FeeFunc) ([]SiacoinOutput, error) {
+	shuffled := make([]SiacoinOutput, 0, len(candidates))
+	for _, i := range fastrand.Perm(len(candidates)) {
+		shuffled = append(shuffled, candidates[i])
+	}
+	return accumulate(shuffled, target, fee)
+}
+
+// SelectCoins implements CoinSelector. A depth-first search over the outputs,
+// largest first, looks for a selection whose value is between the target plus
+// the fee without change and the target plus the fee with change.
+func (bnb BranchAndBoundSelector) SelectCoins(candidates []SiacoinOutput, target types.Currency, fee FeeFunc) ([]SiacoinOutput, error) {
+	sorted := sortByValue(candidates, true)
+
+	// remaining[i] is the total value of the outputs from i onwards, used to
+	// prune branches that cannot reach the target
+	remaining := make([]types.Currency, len(sorted)+1)
+	for i := len(sorted) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1].Add(sorted[i].Value)
+	}
+
+	var (
+		tries    int
+		selected []SiacoinOutput
+		found    []SiacoinOutput
+	)
+
+	var search func(i int, sum types.Currency) bool
+	search = func(i int, sum types.Currency) bool {
+		tries++
+		if tries > bnbMaxTries {
+			return false
+		}
+
+		if len(selected) != 0 {
+			low := target.Add(fee(selected, false))
+			high := target.Add(fee(selected, true))
+			switch {
+			case sum.Cmp(high) > 0:
+				return false
+			case sum.Cmp(low) >= 0:
+				found = append([]SiacoinOutput(nil), selected...)
+				return true
+			}
+		}
+
+		if i == len(sorted) || sum.Add(remaining[i]).Cmp(target) < 0 {
+			return false
+		}
+
+		// include the output, then try without it
+		selected = append(selected, sorted[i])
+		if search(i+1, sum.Add(sorted[i].Value)) {
+			return true
+		}
+		selected = selected[:len(selected)-1]
+		return search(i+1, sum)
+	}
+
+	if search(0, types.ZeroCurrency) {
+		return found, nil
+	}
+
+	fallback := bnb.Fallback
+	if fallback == nil {
+		fallback = LargestFirstSelector{}
+	}
+	return fallback.SelectCoins(candidates, target, fee)
+}
+
+// SelectCoins implements CoinSelector
+func (PrivacySelector) SelectCoins(candidates []SiacoinOutput, target types.Currency, fee FeeFunc) ([]SiacoinOutput, error) {
+	groups := make(map[UnlockHash][]SiacoinOutput)
+	for _, o := range candidates {
+		groups[o.UnlockHash] = append(groups[o.UnlockHash], o)
+	}
+
+	addresses := make([]UnlockHash, 0, len(groups))
+	for addr := range groups {
+		addresses = append(addresses, addr)
+	}
+	sortUnlockHashes(addresses)
+
+	// prefer the smallest single address that can fund the transaction
+	var best []SiacoinOutput
+	for _, addr := range addresses {
+		selected, err := accumulate(sortByValue(groups[addr], true), target, fee)
+		if err != nil {
+			continue
+		} else if best == nil || sumOutputs(selected).Cmp(sumOutputs(best)) < 0 {
+			best = selected
+		}
+	}
+
+	if best != nil {
+		return best, nil
+	}
+
+	// combine whole addresses, largest first, so the fewest addresses are
+	// linked
+	sort.SliceStable(addresses, func(i, j int) bool {
+		return sumOutputs(groups[addresses[i]]).Cmp(sumOutputs(groups[addresses[j]])) > 0
+	})
+
+	var selected []SiacoinOutput
+	for _, addr := range addresses {
+		selected = append(selected, groups[addr]...)
+		if covers(selected, target, fee) {
+			return selected, nil
+		}
+	}
+
+	return nil, ErrInsufficientFunds
+}
@@ -0,0 +1,215 @@
+package sia
+
+import (
+	"errors"
+	"testing"
+
+	"gitlab.com/NebulousLabs/fastrand"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+// testFee charges 1 hasting per input and 3 hastings for a change output
+func testFee(inputs []SiacoinOutput, change bool) types.Currency {
+	fee := types.NewCurrency64(uint64(len(inputs)))
+	if change {
+		fee = fee.Add64(3)
+	}
+	return fee
+}
+
+func TestCoinSelectors(t *testing.T) {
+	a, b, c := UnlockHash{1}, UnlockHash{2}, UnlockHash{3}
+	output := func(id byte, addr UnlockHash, value uint64) SiacoinOutput {
+		return SiacoinOutput{OutputID: OutputID{id}, UnlockHash: addr, Value: types.NewCurrency64(value)}
+	}
+
+	tests := []struct {
+		name       string
+		selector   CoinSelector
+		candidates []SiacoinOutput
+		target     uint64
+		expected   []byte
+		err        error
+	}{
+		{
+			name:       "largest first",
+			selector:   LargestFirstSelector{},
+			candidates: []SiacoinOutput{output(1, a, 1), output(2, a, 5), output(3, a, 10)},
+			target:     8,
+			expected:   []byte{3},
+		},
+		{
+			name:       "smallest first",
+			selector:   SmallestFirstSelector{},
+			candidates: []SiacoinOutput{output(1, a, 1), output(2, a, 5), output(3, a, 10)},
+			target:     8,
+			expected:   []byte{1, 2, 3},
+		},
+		{
+			// 20 exceeds the target plus the fee with change, 6 + 5 is
+			// within the range that needs no change
+			name:       "branch and bound exact match",
+			selector:   BranchAndBoundSelector{},
+			candidates: []SiacoinOutput{output(1, a, 20), output(2, a, 6), output(3, a, 5)},
+			target:     9,
+			expected:   []byte{2, 3},
+		},
+		{
+			name:       "branch and bound lower bound",
+			selector:   BranchAndBoundSelector{},
+			candidates: []SiacoinOutput{output(1, a, 20), output(2, a, 10)},
+			target:     9,
+			expected:   []byte{2},
+		},
+		{
+			name:       "branch and bound default fallback",
+			selector:   BranchAndBoundSelector{},
+			candidates: []SiacoinOutput{output(1, a, 20), output(2, a, 30)},
+			target:     9,
+			expected:   []byte{2},
+		},
+		{
+			name:       "branch and bound fallback",
+			selector:   BranchAndBoundSelector{Fallback: SmallestFirstSelector{}},
+			candidates: []SiacoinOutput{output(1, a, 20), output(2, a, 30)},
+			target:     9,
+			expected:   []byte{1},
+		},
+		{
+			name:       "privacy single address",
+			selector:   PrivacySelector{},
+			candidates: []SiacoinOutput{output(1, a, 5), output(2, a, 5), output(3, b, 12), output(4, c, 50)},
+			target:     9,
+			expected:   []byte{3},
+		},
+		{
+			name:       "privacy combines whole addresses",
+			selector:   PrivacySelector{},
+			candidates: []SiacoinOutput{output(1, a, 5), output(2, a, 5), output(3, b, 6)},
+			target:     12,
+			expected:   []byte{1, 2, 3},
+		},
+		{
+			name:       "insufficient",
+			selector:   LargestFirstSelector{},
+			candidates: []SiacoinOutput{output(1, a, 5)},
+			target:     5,
+			err:        ErrInsufficientFunds,
+		},
+		{
+			name:       "privacy insufficient",
+			selector:   PrivacySelector{},
+			candidates: []SiacoinOutput{output(1, a, 5), output(2, b, 5)},
+			target:     9,
+			err:        ErrInsufficientFunds,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := tt.selector.SelectCoins(tt.candidates, types.NewCurrency64(tt.target), testFee)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected %v, got %v", tt.err, err)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			ids := make(map[byte]bool)
+			for _, o := range selected {
+				ids[o.OutputID[0]] = true
+			}
+			if len(ids) != len(tt.expected) || len(selected) != len(tt.expected) {
+				t.Fatalf("expected outputs %v, got %v", tt.expected, ids)
+			}
+			for _, id := range tt.expected {
+				if !ids[id] {
+					t.Fatalf("expected outputs %v, got %v", tt.expected, ids)
+				}
+			}
+
+			if !covers(selected, types.NewCurrency64(tt.target), testFee) {
+				t.Fatal("selection does not cover the target and fee")
+			}
+		})
+	}
+}
+
+func TestSpendableOutputs(t *testing.T) {
+	outputs := []SiacoinOutput{
+		{OutputID: OutputID{1}, Value: types.NewCurrency64(1)},
+		{OutputID: OutputID{2}, Value: types.NewCurrency64(1), MaturityHeight: 101},
+		{OutputID: OutputID{3}, Value: types.NewCurrency64(1), SpentTransactionID: TransactionID{1}},
+		{OutputID: OutputID{4}, Value: types.NewCurrency64(1)},
+		{OutputID: OutputID{5}, Value: types.NewCurrency64(1), MaturityHeight: 100},
+	}
+	unconfirmed := []Transaction{{
+		SiacoinInputs: []SiacoinInput{{SiacoinOutput: SiacoinOutput{OutputID: OutputID{4}}}},
+	}}
+
+	spendable := spendableOutputs(outputs, 100, unconfirmed)
+	if len(spendable) != 2 || spendable[0].OutputID != (OutputID{1}) || spendable[1].OutputID != (OutputID{5}) {
+		t.Fatalf("unexpected spendable outputs %v", spendable)
+	}
+}
+
+func TestCoinSelectorsBuildValidTransactions(t *testing.T) {
+	const height = 300000
+
+	var seed modules.Seed
+	fastrand.Read(seed[:])
+	ks := NewKeystore()
+	addrs := ks.AddSeed(seed, 3)
+
+	sc := func(n uint64) types.Currency { return types.SiacoinPrecision.Mul64(n) }
+	outputs := []SiacoinOutput{
+		{OutputID: OutputID{1}, UnlockHash: addrs[0], Value: sc(100)},
+		{OutputID: OutputID{2}, UnlockHash: addrs[0], Value: sc(31)},
+		{OutputID: OutputID{3}, UnlockHash: addrs[1], Value: sc(1)},
+		{OutputID: OutputID{4}, UnlockHash: addrs[1], Value: sc(2)},
+		{OutputID: OutputID{5}, UnlockHash: addrs[2], Value: sc(40)},
+	}
+
+	selectors := map[string]CoinSelector{
+		"largest first":    LargestFirstSelector{},
+		"smallest first":   SmallestFirstSelector{},
+		"branch and bound": BranchAndBoundSelector{},
+		"random":           RandomSelector{},
+		"privacy":          PrivacySelector{},
+	}
+
+	for name, selector := range selectors {
+		t.Run(name, func(t *testing.T) {
+			utxn, err := BuildTransaction(BuildOptions{
+				Outputs:          outputs,
+				UnlockConditions: ks.UnlockConditions(),
+				Recipients:       []types.SiacoinOutput{{Value: sc(30), UnlockHash: types.UnlockHash{9}}},
+				ChangeAddress:    addrs[0],
+				Height:           height,
+				Selector:         selector,
+				FeePerByte:       types.SiacoinPrecision.Div64(1e6),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			txn := utxn.Transaction
+			total := utxn.Fee
+			for _, o := range txn.SiacoinOutputs {
+				total = total.Add(o.Value)
+			}
+			if !sumOutputs(utxn.Inputs).Equals(total) {
+				t.Fatal("inputs do not equal outputs plus fee")
+			}
+
+			if err := ks.SignTransaction(&txn, height); err != nil {
+				t.Fatal(err)
+			} else if err := txn.StandaloneValid(height); err != nil {
+				t.Fatalf("signed transaction is invalid: %s", err)
+			}
+		})
+	}
+}
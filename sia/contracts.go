@@ -0,0 +1,128 @@
+package sia
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// indexes of the standard contract proof outputs
+	renterOutputIndex = 0
+	hostOutputIndex   = 1
+	voidOutputIndex   = 2
+)
+
+// ContractRevisionDelta is the change between two consecutive revisions of a
+// contract. Currency values are in hastings and are signed, a negative value
+// means the amount moved in the opposite direction.
+type ContractRevisionDelta struct {
+	RevisionNumber         uint64        `json:"revision_number"`
+	PreviousRevisionNumber uint64        `json:"previous_revision_number"`
+	TransactionID          TransactionID `json:"transaction_id"`
+	BlockID                BlockID       `json:"block_id"`
+	Height                 uint64        `json:"height"`
+	Timestamp              time.Time     `json:"timestamp"`
+
+	// FileSize is the size of the contract's data after the revision and
+	// DataAdded is the change in size in bytes
+	FileSize  uint64 `json:"file_size"`
+	DataAdded int64  `json:"data_added"`
+
+	// RenterSpending is the decrease of the renter's valid proof output
+	RenterSpending decimal.Decimal `json:"renter_spending"`
+	// HostEarnings is the increase of the host's valid proof output
+	HostEarnings decimal.Decimal `json:"host_earnings"`
+	// CollateralMoved is the increase of the host's collateral at risk, the
+	// amount moved from the host's missed proof output to the void
+	CollateralMoved decimal.Decimal `json:"collateral_moved"`
+
+	MerkleRootChanged  bool   `json:"merkle_root_changed"`
+	PreviousMerkleRoot string `json:"previous_merkle_root"`
+	MerkleRoot         string `json:"merkle_root"`
+}
+
+// outputValue returns the value of the output at index i or zero if the
+// output does not exist
+func outputValue(outputs []SiacoinOutput, i int) decimal.Decimal {
+	if i >= len(outputs) {
+		return decimal.Zero
+	}
+
+	return decimal.NewFromBigInt(outputs[i].Value.Big(), 0)
+}
+
+// collateralAtRisk returns the amount of the host's collateral that would be
+// lost if the host fails to submit a storage proof
+func collateralAtRisk(c StorageContract) decimal.Decimal {
+	if len(c.MissedProofOutputs) > voidOutputIndex {
+		return outputValue(c.MissedProofOutputs, voidOutputIndex)
+	}
+
+	return outputValue(c.ValidProofOutputs, hostOutputIndex).Sub(outputValue(c.MissedProofOutputs, hostOutputIndex))
+}
+
+// fileSize returns the contract's file size or zero if it overflows
+func fileSize(c StorageContract) uint64 {
+	size, err := c.FileSize.Uint64()
+	if err != nil {
+		return 0
+	}
+
+	return size
+}
+
+// ContractRevisionHistory returns the changes between each of the contract's
+// revisions ordered by revision number. The earliest known revision is used as
+// the baseline and has no delta of its own. The height and timestamp of
+// each delta are the negotiation height and timestamp of the revision as
+// reported by the API.
+func ContractRevisionHistory(contract StorageContract) (deltas []ContractRevisionDelta) {
+	revisions := make([]StorageContract, 0, len(contract.PreviousRevisions)+1)
+	revisions = append(revisions, contract.PreviousRevisions...)
+	revisions = append(revisions, contract)
+
+	seen := make(map[uint64]bool)
+	unique := revisions[:0]
+	for _, rev := range revisions {
+		if seen[rev.RevisionNumber] {
+			continue
+		}
+
+		seen[rev.RevisionNumber] = true
+		unique = append(unique, rev)
+	}
+	revisions = unique
+
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].RevisionNumber < revisions[j].RevisionNumber
+	})
+
+	for i := 1; i < len(revisions); i++ {
+		prev, current := revisions[i-1], revisions[i]
+		prevSize, currentSize := fileSize(prev), fileSize(current)
+
+		deltas = append(deltas, ContractRevisionDelta{
+			RevisionNumber:         current.RevisionNumber,
+			PreviousRevisionNumber: prev.RevisionNumber,
+			TransactionID:          current.TransactionID,
+			BlockID:                current.BlockID,
+			Height:                 current.NegotiationHeight,
+			Timestamp:              current.NegotiationTimestamp,
+
+			FileSize:  currentSize,
+			DataAdded: int64(currentSize) - int64(prevSize),
+
+			RenterSpending:  outputValue(prev.ValidProofOutputs, renterOutputIndex).Sub(outputValue(current.ValidProofOutputs, renterOutputIndex)),
+			HostEarnings:    outputValue(current.ValidProofOutputs, hostOutputIndex).Sub(outputValue(prev.ValidProofOutputs, hostOutputIndex)),
+			CollateralMoved: collateralAtRisk(current).Sub(collateralAtRisk(prev)),
+
+			MerkleRootChanged:  current.MerkleRoot != prev.MerkleRoot,
+			PreviousMerkleRoot: prev.MerkleRoot,
+			MerkleRoot:         current.MerkleRoot,
+		})
+	}
+
+	return
+}
@@ -0,0 +1,114 @@
+package sia
+
+import (
+	"testing"
+
+	"go.sia.tech/siad/types"
+)
+
+func testRevision(n, size uint64, root string, valid, missed []uint64) StorageContract {
+	outputs := func(values []uint64) (outputs []SiacoinOutput) {
+		for _, v := range values {
+			outputs = append(outputs, SiacoinOutput{Value: types.NewCurrency64(v)})
+		}
+		return
+	}
+
+	return StorageContract{
+		RevisionNumber:     n,
+		NegotiationHeight:  1000 + n,
+		TransactionID:      TransactionID{byte(n)},
+		FileSize:           types.NewCurrency64(size),
+		MerkleRoot:         root,
+		ValidProofOutputs:  outputs(valid),
+		MissedProofOutputs: outputs(missed),
+	}
+}
+
+func TestContractRevisionHistory(t *testing.T) {
+	type delta struct {
+		revision, previous uint64
+		dataAdded          int64
+		spending, earnings int64
+		collateral         int64
+		rootChanged        bool
+	}
+
+	rev0 := testRevision(0, 0, "a", []uint64{100, 50}, []uint64{100, 50, 0})
+	rev1 := testRevision(1, 10, "b", []uint64{90, 60}, []uint64{90, 40, 20})
+	rev2 := testRevision(2, 4, "c", []uint64{80, 70}, []uint64{80, 40, 30})
+	rev3 := testRevision(3, 4, "c", []uint64{85, 65}, []uint64{85, 50, 20})
+
+	withPrevious := func(c StorageContract, previous ...StorageContract) StorageContract {
+		c.PreviousRevisions = previous
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		contract StorageContract
+		expected []delta
+	}{
+		{
+			name:     "no previous revisions",
+			contract: rev0,
+		},
+		{
+			name:     "ordered",
+			contract: withPrevious(rev3, rev0, rev1, rev2),
+			expected: []delta{
+				{1, 0, 10, 10, 10, 20, true},
+				{2, 1, -6, 10, 10, 10, true},
+				{3, 2, 0, -5, -5, -10, false},
+			},
+		},
+		{
+			name:     "unordered with duplicates",
+			contract: withPrevious(rev3, rev2, rev0, rev2, rev3, rev1),
+			expected: []delta{
+				{1, 0, 10, 10, 10, 20, true},
+				{2, 1, -6, 10, 10, 10, true},
+				{3, 2, 0, -5, -5, -10, false},
+			},
+		},
+		{
+			// without a void output the collateral at risk is the difference
+			// between the host's valid and missed outputs
+			name: "no void output",
+			contract: withPrevious(testRevision(1, 10, "b", []uint64{90, 60}, []uint64{90, 45}),
+				testRevision(0, 0, "a", []uint64{100, 50}, []uint64{100, 50})),
+			expected: []delta{
+				{1, 0, 10, 10, 10, 15, true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deltas := ContractRevisionHistory(tt.contract)
+			if len(deltas) != len(tt.expected) {
+				t.Fatalf("expected %v deltas, got %v", len(tt.expected), len(deltas))
+			}
+
+			for i, d := range deltas {
+				exp := tt.expected[i]
+				switch {
+				case d.RevisionNumber != exp.revision || d.PreviousRevisionNumber != exp.previous:
+					t.Fatalf("delta %v: expected revision %v -> %v, got %v -> %v", i, exp.previous, exp.revision, d.PreviousRevisionNumber, d.RevisionNumber)
+				case d.TransactionID != (TransactionID{byte(exp.revision)}) || d.Height != 1000+exp.revision:
+					t.Fatalf("delta %v: unexpected transaction %v at height %v", i, d.TransactionID, d.Height)
+				case d.DataAdded != exp.dataAdded:
+					t.Fatalf("delta %v: expected %v bytes added, got %v", i, exp.dataAdded, d.DataAdded)
+				case d.RenterSpending.IntPart() != exp.spending:
+					t.Fatalf("delta %v: expected renter spending %v, got %v", i, exp.spending, d.RenterSpending)
+				case d.HostEarnings.IntPart() != exp.earnings:
+					t.Fatalf("delta %v: expected host earnings %v, got %v", i, exp.earnings, d.HostEarnings)
+				case d.CollateralMoved.IntPart() != exp.collateral:
+					t.Fatalf("delta %v: expected collateral moved %v, got %v", i, exp.collateral, d.CollateralMoved)
+				case d.MerkleRootChanged != exp.rootChanged:
+					t.Fatalf("delta %v: expected merkle root changed %v", i, exp.rootChanged)
+				}
+			}
+		})
+	}
+}
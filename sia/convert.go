@@ -0,0 +1,249 @@
+package sia
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/types"
+)
+
+var (
+	// ErrRevisionUnverifiable is returned when a transaction contains
+	// contract revisions, which cannot be reconstructed from the API and so
+	// cannot be verified
+	ErrRevisionUnverifiable = errors.New("contract revisions cannot be reconstructed")
+)
+
+func parseHash(s string) (h crypto.Hash, err error) {
+	err = h.LoadString(s)
+	return
+}
+
+// parseSignature decodes a transaction signature. The API encodes signatures
+// as hex, but siad encodes them as base64 so both are accepted.
+func parseSignature(s string) ([]byte, error) {
+	if buf, err := hex.DecodeString(s); err == nil {
+		return buf, nil
+	}
+
+	return base64.StdEncoding.DecodeString(s)
+}
+
+func (uc UnlockCondition) siadUnlockConditions() (conditions types.UnlockConditions) {
+	conditions.Timelock = types.BlockHeight(uc.Timelock)
+	conditions.SignaturesRequired = uc.RequiredSignatures
+
+	for _, key := range uc.PublicKeys {
+		conditions.PublicKeys = append(conditions.PublicKeys, key.Siad())
+	}
+
+	return
+}
+
+func (o SiacoinOutput) siadSiacoinOutput() types.SiacoinOutput {
+	return types.SiacoinOutput{
+		Value:      o.Value,
+		UnlockHash: o.UnlockHash.Siad(),
+	}
+}
+
+func siadSiacoinOutputs(outputs []SiacoinOutput) (siad []types.SiacoinOutput) {
+	for _, o := range outputs {
+		siad = append(siad, o.siadSiacoinOutput())
+	}
+
+	return
+}
+
+func (c StorageContract) siadFileContract() (fc types.FileContract, err error) {
+	fc.FileSize, err = c.FileSize.Uint64()
+	if err != nil {
+		err = fmt.Errorf("unable to convert file size: %w", err)
+		return
+	}
+
+	fc.FileMerkleRoot, err = parseHash(c.MerkleRoot)
+	if err != nil {
+		err = fmt.Errorf("unable to parse merkle root %s: %w", c.MerkleRoot, err)
+		return
+	}
+
+	fc.UnlockHash = c.UnlockHash.Siad()
+	fc.ValidProofOutputs = siadSiacoinOutputs(c.ValidProofOutputs)
+	fc.MissedProofOutputs = siadSiacoinOutputs(c.MissedProofOutputs)
+	fc.WindowStart = types.BlockHeight(c.ExpirationHeight)
+	fc.WindowEnd = types.BlockHeight(c.ProofDeadline)
+	fc.Payout = c.Payout
+	fc.RevisionNumber = c.RevisionNumber
+	return
+}
+
+func (sp StorageProof) siadStorageProof() (proof types.StorageProof, err error) {
+	proof.ParentID = sp.ContractID.Siad()
+	proof.Segment = sp.Segment
+
+	for _, s := range sp.Hashset {
+		var h crypto.Hash
+
+		h, err = parseHash(s)
+		if err != nil {
+			err = fmt.Errorf("unable to parse proof hash %s: %w", s, err)
+			return
+		}
+
+		proof.HashSet = append(proof.HashSet, h)
+	}
+
+	return
+}
+
+func (cf CoveredFields) siadCoveredFields() types.CoveredFields {
+	return types.CoveredFields{
+		WholeTransaction:      cf.WholeTransaction,
+		SiacoinInputs:         cf.SiacoinInputs,
+		SiacoinOutputs:        cf.SiacoinOutputs,
+		FileContracts:         cf.StorageContracts,
+		FileContractRevisions: cf.StorageContractRevisions,
+		StorageProofs:         cf.StorageProofs,
+		MinerFees:             cf.MinerFees,
+		ArbitraryData:         cf.ArbitraryData,
+		TransactionSignatures: cf.TransactionSignatures,
+	}
+}
+
+func (ts TransactionSignature) siadTransactionSignature() (sig types.TransactionSignature, err error) {
+	sig.ParentID = ts.ParentID.Siad()
+	sig.Signature, err = parseSignature(ts.Signature)
+	if err != nil {
+		err = fmt.Errorf("unable to decode signature: %w", err)
+		return
+	}
+
+	sig.PublicKeyIndex = ts.PublicKeyIndex
+	sig.CoveredFields = ts.CoveredFields.siadCoveredFields()
+	return
+}
+
+// SiadTransaction converts the transaction into a siad transaction. The API
+// does not return the unlock conditions of contract revisions, signature
+// timelocks or the siafund covered fields of partial signatures, so
+// transactions containing revisions cannot be converted and signatures are
+// assumed to have no timelock.
+func (t Transaction) SiadTransaction() (txn types.Transaction, err error) {
+	if len(t.ContractRevisions) != 0 {
+		err = fmt.Errorf("transaction %s: %w", t.ID, ErrRevisionUnverifiable)
+		return
+	}
+
+	for _, sci := range t.SiacoinInputs {
+		txn.SiacoinInputs = append(txn.SiacoinInputs, types.SiacoinInput{
+			ParentID:         sci.OutputID.SiacoinOutputID(),
+			UnlockConditions: sci.UnlockConditions.siadUnlockConditions(),
+		})
+	}
+
+	txn.SiacoinOutputs = siadSiacoinOutputs(t.SiacoinOutputs)
+
+	for _, c := range t.StorageContracts {
+		var fc types.FileContract
+
+		fc, err = c.siadFileContract()
+		if err != nil {
+			return
+		}
+
+		txn.FileContracts = append(txn.FileContracts, fc)
+	}
+
+	for _, sp := range t.StorageProofs {
+		var proof types.StorageProof
+
+		proof, err = sp.siadStorageProof()
+		if err != nil {
+			return
+		}
+
+		txn.StorageProofs = append(txn.StorageProofs, proof)
+	}
+
+	for _, sfi := range t.SiafundInputs {
+		txn.SiafundInputs = append(txn.SiafundInputs, types.SiafundInput{
+			ParentID:         sfi.OutputID.SiafundOutputID(),
+			UnlockConditions: sfi.UnlockConditions.siadUnlockConditions(),
+			ClaimUnlockHash:  sfi.ClaimUnlockHash.Siad(),
+		})
+	}
+
+	for _, sfo := range t.SiafundOutputs {
+		// the claim start is set by consensus when the transaction is
+		// processed, it is always zero in the transaction itself
+		txn.SiafundOutputs = append(txn.SiafundOutputs, types.SiafundOutput{
+			Value:      sfo.Value,
+			UnlockHash: sfo.UnlockHash.Siad(),
+			ClaimStart: types.ZeroCurrency,
+		})
+	}
+
+	txn.MinerFees = append(txn.MinerFees, t.MinerFees...)
+	txn.ArbitraryData = append(txn.ArbitraryData, t.ArbitraryData...)
+
+	for _, ts := range t.TransactionSignatures {
+		var sig types.TransactionSignature
+
+		sig, err = ts.siadTransactionSignature()
+		if err != nil {
+			return
+		}
+
+		txn.TransactionSignatures = append(txn.TransactionSignatures, sig)
+	}
+
+	return
+}
+
+// minerPayouts returns the outputs of the block that are miner payouts in
+// payout index order. The API may include other outputs created by the block,
+// such as the Foundation subsidy, and does not guarantee their order, so
+// outputs are matched against the expected id of each payout index.
+func (b Block) minerPayouts() (payouts []SiacoinOutput) {
+	outputs := make(map[OutputID]SiacoinOutput, len(b.SiacoinOutputs))
+	for _, o := range b.SiacoinOutputs {
+		outputs[o.OutputID] = o
+	}
+
+	for i := range b.SiacoinOutputs {
+		o, exists := outputs[OutputID(crypto.HashAll(b.ID.Siad(), uint64(i)))]
+		if !exists {
+			break
+		}
+
+		payouts = append(payouts, o)
+	}
+
+	return
+}
+
+// SiadBlock converts the block into a siad block. Only the outputs of the
+// block that are miner payouts are included.
+func (b Block) SiadBlock() (block types.Block, err error) {
+	block.ParentID = b.ParentID.Siad()
+	block.Nonce = types.BlockNonce(b.Nonce)
+	block.Timestamp = types.Timestamp(b.Timestamp.Unix())
+	block.MinerPayouts = siadSiacoinOutputs(b.minerPayouts())
+
+	for _, t := range b.Transactions {
+		var txn types.Transaction
+
+		txn, err = t.SiadTransaction()
+		if err != nil {
+			return
+		}
+
+		block.Transactions = append(block.Transactions, txn)
+	}
+
+	return
+}
@@ -0,0 +1,186 @@
+package sia
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// ContractStatusActive the contract's proof window has not ended
+	ContractStatusActive ContractStatus = "active"
+	// ContractStatusSuccessful a valid storage proof was submitted for the
+	// contract
+	ContractStatusSuccessful ContractStatus = "successful"
+	// ContractStatusFailed the contract's proof deadline passed without a
+	// storage proof
+	ContractStatusFailed ContractStatus = "failed"
+	// ContractStatusRejected the contract was never confirmed on the
+	// blockchain
+	ContractStatusRejected ContractStatus = "rejected"
+
+	// OutputSourceTransaction the output was created by a transaction
+	OutputSourceTransaction OutputSource = "transaction"
+	// OutputSourceMinerPayout the output was created as a block's miner payout
+	OutputSourceMinerPayout OutputSource = "miner_payout"
+	// OutputSourceValidProof the output was created by a contract's valid proof
+	// outputs
+	OutputSourceValidProof OutputSource = "contract_valid_output"
+	// OutputSourceMissedProof the output was created by a contract's missed
+	// proof outputs
+	OutputSourceMissedProof OutputSource = "contract_missed_output"
+	// OutputSourceSiafundClaim the output was created by claiming siafund
+	// revenue
+	OutputSourceSiafundClaim OutputSource = "siafund_claim"
+	// OutputSourceFoundationSubsidy the output was created by the Foundation
+	// subsidy
+	OutputSourceFoundationSubsidy OutputSource = "foundation_subsidy"
+
+	// AddressUsageSiacoinInput the address spent a siacoin output
+	AddressUsageSiacoinInput AddressUsageType = "siacoin_input"
+	// AddressUsageSiacoinOutput the address received a siacoin output
+	AddressUsageSiacoinOutput AddressUsageType = "siacoin_output"
+	// AddressUsageSiafundInput the address spent a siafund output
+	AddressUsageSiafundInput AddressUsageType = "siafund_input"
+	// AddressUsageSiafundOutput the address received a siafund output
+	AddressUsageSiafundOutput AddressUsageType = "siafund_output"
+	// AddressUsageContract the address was used in a storage contract
+	AddressUsageContract AddressUsageType = "contract"
+	// AddressUsageMinerPayout the address received a miner payout
+	AddressUsageMinerPayout AddressUsageType = "miner_payout"
+)
+
+type (
+	// ContractStatus the status of a storage contract
+	ContractStatus string
+
+	// OutputSource what created a siacoin output
+	OutputSource string
+
+	// AddressUsageType how an address was used on the blockchain
+	AddressUsageType string
+)
+
+// unmarshalEnum decodes a JSON string into an enum value. Values that are not a
+// JSON string are rejected, unknown values are kept so callers can check them
+// with Known.
+func unmarshalEnum(b []byte, name string) (string, error) {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return "", fmt.Errorf("unable to unmarshal %s: %w", name, err)
+	}
+
+	return s, nil
+}
+
+// Known returns true if the status is one of the known contract statuses
+func (cs ContractStatus) Known() bool {
+	switch cs {
+	case ContractStatusActive, ContractStatusSuccessful, ContractStatusFailed, ContractStatusRejected:
+		return true
+	}
+	return false
+}
+
+// IsFinal returns true if the contract has been resolved and its status will
+// not change
+func (cs ContractStatus) IsFinal() bool {
+	switch cs {
+	case ContractStatusSuccessful, ContractStatusFailed, ContractStatusRejected:
+		return true
+	}
+	return false
+}
+
+// String returns the status as a string
+func (cs ContractStatus) String() string {
+	return string(cs)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (cs *ContractStatus) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalEnum(b, "contract status")
+	if err != nil {
+		return err
+	}
+
+	*cs = ContractStatus(s)
+	return nil
+}
+
+// Known returns true if the source is one of the known output sources
+func (src OutputSource) Known() bool {
+	switch src {
+	case OutputSourceTransaction, OutputSourceMinerPayout, OutputSourceValidProof,
+		OutputSourceMissedProof, OutputSourceSiafundClaim, OutputSourceFoundationSubsidy:
+		return true
+	}
+	return false
+}
+
+// IsMinerPayout returns true if the output is a block's miner payout
+func (src OutputSource) IsMinerPayout() bool {
+	return src == OutputSourceMinerPayout
+}
+
+// IsContractPayout returns true if the output was created by the valid or
+// missed proof outputs of a storage contract
+func (src OutputSource) IsContractPayout() bool {
+	return src == OutputSourceValidProof || src == OutputSourceMissedProof
+}
+
+// IsTransactionOutput returns true if the output was created by a transaction
+func (src OutputSource) IsTransactionOutput() bool {
+	return src == OutputSourceTransaction
+}
+
+// String returns the source as a string
+func (src OutputSource) String() string {
+	return string(src)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (src *OutputSource) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalEnum(b, "output source")
+	if err != nil {
+		return err
+	}
+
+	*src = OutputSource(s)
+	return nil
+}
+
+// Known returns true if the usage type is one of the known usage types
+func (ut AddressUsageType) Known() bool {
+	switch ut {
+	case AddressUsageSiacoinInput, AddressUsageSiacoinOutput, AddressUsageSiafundInput,
+		AddressUsageSiafundOutput, AddressUsageContract, AddressUsageMinerPayout:
+		return true
+	}
+	return false
+}
+
+// IsInput returns true if the address spent an output
+func (ut AddressUsageType) IsInput() bool {
+	return ut == AddressUsageSiacoinInput || ut == AddressUsageSiafundInput
+}
+
+// IsOutput returns true if the address received an output
+func (ut AddressUsageType) IsOutput() bool {
+	return ut == AddressUsageSiacoinOutput || ut == AddressUsageSiafundOutput || ut == AddressUsageMinerPayout
+}
+
+// String returns the usage type as a string
+func (ut AddressUsageType) String() string {
+	return string(ut)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (ut *AddressUsageType) UnmarshalJSON(b []byte) error {
+	s, err := unmarshalEnum(b, "address usage type")
+	if err != nil {
+		return err
+	}
+
+	*ut = AddressUsageType(s)
+	return nil
+}
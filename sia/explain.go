@@ -0,0 +1,227 @@
+package sia
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// TransactionTypeSend siacoins were sent to another address
+	TransactionTypeSend TransactionType = "send"
+	// TransactionTypeReceive siacoins were received from another address
+	TransactionTypeReceive TransactionType = "receive"
+	// TransactionTypeContractFormation a new storage contract was formed
+	TransactionTypeContractFormation TransactionType = "contract_formation"
+	// TransactionTypeContractRenewal a storage contract was renewed, the
+	// transaction forms a new contract and revises the old one
+	TransactionTypeContractRenewal TransactionType = "contract_renewal"
+	// TransactionTypeContractRevision a storage contract was revised
+	TransactionTypeContractRevision TransactionType = "contract_revision"
+	// TransactionTypeStorageProof a host submitted a storage proof
+	TransactionTypeStorageProof TransactionType = "storage_proof"
+	// TransactionTypeHostAnnouncement a host announced itself on the network
+	TransactionTypeHostAnnouncement TransactionType = "host_announcement"
+	// TransactionTypeSiafundClaim siafunds were spent, claiming their accrued
+	// siacoin revenue
+	TransactionTypeSiafundClaim TransactionType = "siafund_claim"
+	// TransactionTypeDefrag siacoin outputs were consolidated back into the
+	// same wallet
+	TransactionTypeDefrag TransactionType = "defrag"
+	// TransactionTypeUnrelated siacoins were transferred without involving
+	// the addresses the transaction was explained for
+	TransactionTypeUnrelated TransactionType = "unrelated"
+)
+
+type (
+	// TransactionType the classification of a transaction
+	TransactionType string
+
+	// TransactionSummary a structured explanation of a transaction. Own values
+	// are relative to the set of addresses the transaction was explained for.
+	TransactionSummary struct {
+		ID        TransactionID   `json:"id"`
+		Type      TransactionType `json:"type"`
+		Height    uint64          `json:"height"`
+		Timestamp time.Time       `json:"timestamp"`
+
+		Fees           types.Currency `json:"fees"`
+		SiacoinInputs  types.Currency `json:"siacoin_inputs"`
+		SiacoinOutputs types.Currency `json:"siacoin_outputs"`
+		OwnInputs      types.Currency `json:"own_inputs"`
+		OwnOutputs     types.Currency `json:"own_outputs"`
+		// Net is the change in the balance of the own addresses in hastings,
+		// negative if siacoins were spent
+		Net decimal.Decimal `json:"net"`
+
+		Senders    []UnlockHash    `json:"senders"`
+		Recipients []SiacoinOutput `json:"recipients"`
+
+		Contracts     []ContractID   `json:"contracts"`
+		Revisions     []ContractID   `json:"revisions"`
+		StorageProofs []ContractID   `json:"storage_proofs"`
+		Announcements []Announcement `json:"announcements"`
+		SiafundInputs types.Currency `json:"siafund_inputs"`
+
+		Description string `json:"description"`
+	}
+)
+
+// classifyTransaction determines the type of the transaction from its
+// contents. Contract and host actions take precedence over siacoin transfers.
+func classifyTransaction(t Transaction, s TransactionSummary, own map[UnlockHash]bool) TransactionType {
+	switch {
+	case len(t.StorageProofs) != 0:
+		return TransactionTypeStorageProof
+	case len(t.StorageContracts) != 0 && len(t.ContractRevisions) != 0:
+		return TransactionTypeContractRenewal
+	case len(t.StorageContracts) != 0:
+		return TransactionTypeContractFormation
+	case len(t.ContractRevisions) != 0:
+		return TransactionTypeContractRevision
+	case len(t.HostAnnouncements) != 0:
+		return TransactionTypeHostAnnouncement
+	case len(t.SiafundInputs) != 0:
+		return TransactionTypeSiafundClaim
+	}
+
+	// without a set of own addresses, outputs that all go back to the
+	// addresses being spent from are a defrag
+	inputAddresses := make(map[UnlockHash]bool)
+	for _, sci := range t.SiacoinInputs {
+		inputAddresses[sci.UnlockHash] = true
+	}
+
+	if len(own) == 0 {
+		own = inputAddresses
+	} else if s.OwnInputs.IsZero() {
+		if s.OwnOutputs.IsZero() {
+			return TransactionTypeUnrelated
+		}
+		return TransactionTypeReceive
+	}
+
+	for _, sco := range t.SiacoinOutputs {
+		if !own[sco.UnlockHash] {
+			return TransactionTypeSend
+		}
+	}
+
+	if len(t.SiacoinInputs) == 0 {
+		return TransactionTypeSend
+	}
+	return TransactionTypeDefrag
+}
+
+// describeTransaction returns a human-readable description of the summary
+func describeTransaction(s TransactionSummary) string {
+	plural := func(n int, word string) string {
+		if n == 1 {
+			return fmt.Sprintf("%d %s", n, word)
+		}
+		return fmt.Sprintf("%d %ss", n, word)
+	}
+
+	var sb strings.Builder
+	switch s.Type {
+	case TransactionTypeStorageProof:
+		fmt.Fprintf(&sb, "Submitted storage proofs for %s", plural(len(s.StorageProofs), "contract"))
+	case TransactionTypeContractRenewal:
+		fmt.Fprintf(&sb, "Renewed %s into %s", plural(len(s.Revisions), "contract"), plural(len(s.Contracts), "new contract"))
+	case TransactionTypeContractFormation:
+		fmt.Fprintf(&sb, "Formed %s", plural(len(s.Contracts), "storage contract"))
+	case TransactionTypeContractRevision:
+		fmt.Fprintf(&sb, "Revised %s", plural(len(s.Revisions), "storage contract"))
+	case TransactionTypeHostAnnouncement:
+		addresses := make([]string, 0, len(s.Announcements))
+		for _, a := range s.Announcements {
+			addresses = append(addresses, a.NetAddress)
+		}
+		fmt.Fprintf(&sb, "Announced host at %s", strings.Join(addresses, ", "))
+	case TransactionTypeSiafundClaim:
+		fmt.Fprintf(&sb, "Spent %s SF, claiming the accrued siafund revenue", s.SiafundInputs)
+	case TransactionTypeDefrag:
+		fmt.Fprintf(&sb, "Consolidated %s", s.SiacoinInputs.HumanString())
+	case TransactionTypeReceive:
+		fmt.Fprintf(&sb, "Received %s from %s", s.OwnOutputs.HumanString(), plural(len(s.Senders), "address"))
+	case TransactionTypeUnrelated:
+		fmt.Fprintf(&sb, "Transferred %s between other addresses", s.SiacoinOutputs.HumanString())
+	default:
+		sent := types.ZeroCurrency
+		for _, o := range s.Recipients {
+			sent = sent.Add(o.Value)
+		}
+		fmt.Fprintf(&sb, "Sent %s to %s", sent.HumanString(), plural(len(s.Recipients), "output"))
+	}
+
+	if !s.Fees.IsZero() {
+		fmt.Fprintf(&sb, " with a fee of %s", s.Fees.HumanString())
+	}
+
+	return sb.String()
+}
+
+// ExplainTransaction classifies the transaction and summarizes its inputs,
+// outputs, contract actions and announcements. If own addresses are provided,
+// the summary is relative to them: outputs to own addresses are treated as
+// change and the net balance change of the addresses is calculated.
+func ExplainTransaction(t Transaction, own ...UnlockHash) (s TransactionSummary) {
+	ownAddresses := make(map[UnlockHash]bool, len(own))
+	for _, addr := range own {
+		ownAddresses[addr] = true
+	}
+
+	s.ID = t.ID
+	s.Height = t.BlockHeight
+	s.Timestamp = t.Timestamp
+	s.Fees = t.totalFees()
+
+	inputAddresses := make(map[UnlockHash]bool)
+	for _, sci := range t.SiacoinInputs {
+		s.SiacoinInputs = s.SiacoinInputs.Add(sci.Value)
+		if ownAddresses[sci.UnlockHash] {
+			s.OwnInputs = s.OwnInputs.Add(sci.Value)
+		} else if !inputAddresses[sci.UnlockHash] {
+			s.Senders = append(s.Senders, sci.UnlockHash)
+		}
+		inputAddresses[sci.UnlockHash] = true
+	}
+
+	for _, sco := range t.SiacoinOutputs {
+		s.SiacoinOutputs = s.SiacoinOutputs.Add(sco.Value)
+		switch {
+		case ownAddresses[sco.UnlockHash]:
+			s.OwnOutputs = s.OwnOutputs.Add(sco.Value)
+		case len(ownAddresses) == 0 && inputAddresses[sco.UnlockHash]:
+			// without a set of own addresses, outputs returning to an
+			// input address are assumed to be change
+		default:
+			s.Recipients = append(s.Recipients, sco)
+		}
+	}
+
+	for _, sfi := range t.SiafundInputs {
+		s.SiafundInputs = s.SiafundInputs.Add(sfi.Value)
+	}
+
+	for _, c := range t.StorageContracts {
+		s.Contracts = append(s.Contracts, c.ID)
+	}
+
+	for _, c := range t.ContractRevisions {
+		s.Revisions = append(s.Revisions, c.ID)
+	}
+
+	for _, sp := range t.StorageProofs {
+		s.StorageProofs = append(s.StorageProofs, sp.ContractID)
+	}
+
+	s.Announcements = append(s.Announcements, t.HostAnnouncements...)
+	s.Net = decimal.NewFromBigInt(s.OwnOutputs.Big(), 0).Sub(decimal.NewFromBigInt(s.OwnInputs.Big(), 0))
+	s.Type = classifyTransaction(t, s, ownAddresses)
+	s.Description = describeTransaction(s)
+	return
+}
@@ -6,6 +6,12 @@ import (
 	"net/http"
 )
 
+const (
+	// maxBatchSize is the maximum number of ids, heights or addresses the API
+	// accepts in a single request
+	maxBatchSize = 10000
+)
+
 type (
 	getBlockResp struct {
 		APIResponse
@@ -43,6 +49,23 @@ type (
 	}
 )
 
+// batches calls fn with consecutive [start, end) ranges of at most size
+// elements covering n elements, stopping at the first error
+func batches(n, size int, fn func(start, end int) error) error {
+	for start := 0; start < n; start += size {
+		end := start + size
+		if end > n {
+			end = n
+		}
+
+		if err := fn(start, end); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (a *APIClient) GetChainIndex() (index ChainIndex, err error) {
 	var resp getChainIndexResp
 
@@ -79,7 +102,7 @@ func (a *APIClient) GetLatestBlock() (block Block, err error) {
 }
 
 // GetBlockByID returns the block with the matching id in the Sia Central explorer
-func (a *APIClient) GetBlockByID(id string) (block Block, err error) {
+func (a *APIClient) GetBlockByID(id BlockID) (block Block, err error) {
 	var resp getBlockResp
 
 	code, err := a.makeAPIRequest(http.MethodGet, fmt.Sprintf("/explorer/blocks/%s", id), nil, &resp)
@@ -117,7 +140,7 @@ func (a *APIClient) GetBlockByHeight(height uint64) (block Block, err error) {
 }
 
 // FindBlocksByID returns all blocks with the specified ids from the Sia Central explorer
-func (a *APIClient) FindBlocksByID(ids ...string) (blocks []Block, err error) {
+func (a *APIClient) FindBlocksByID(ids ...BlockID) (blocks []Block, err error) {
 	var resp batchBlocksResp
 
 	if len(ids) > 10000 {
@@ -169,7 +192,7 @@ func (a *APIClient) FindBlocksByHeight(heights ...uint64) (blocks []Block, err e
 }
 
 // GetTransactionByID returns the transaction at the specified height in the Sia Central explorer
-func (a *APIClient) GetTransactionByID(id string) (transaction Transaction, err error) {
+func (a *APIClient) GetTransactionByID(id TransactionID) (transaction Transaction, err error) {
 	var resp getTransactionResp
 
 	code, err := a.makeAPIRequest(http.MethodGet, fmt.Sprintf("/explorer/transactions/%s", id), nil, &resp)
@@ -188,7 +211,7 @@ func (a *APIClient) GetTransactionByID(id string) (transaction Transaction, err
 }
 
 // FindTransactionsByID returns all transactions with the specified ids from the Sia Central explorer
-func (a *APIClient) FindTransactionsByID(ids ...string) (transactions []Transaction, err error) {
+func (a *APIClient) FindTransactionsByID(ids ...TransactionID) (transactions []Transaction, err error) {
 	var resp batchTransactionsResp
 
 	if len(ids) > 10000 {
@@ -214,7 +237,7 @@ func (a *APIClient) FindTransactionsByID(ids ...string) (transactions []Transact
 }
 
 // GetContractByID returns the contract at the specified height in the Sia Central explorer
-func (a *APIClient) GetContractByID(id string) (contract StorageContract, err error) {
+func (a *APIClient) GetContractByID(id ContractID) (contract StorageContract, err error) {
 	var resp getContractResp
 
 	code, err := a.makeAPIRequest(http.MethodGet, fmt.Sprintf("/explorer/contracts/%s", id), nil, &resp)
@@ -233,7 +256,7 @@ func (a *APIClient) GetContractByID(id string) (contract StorageContract, err er
 }
 
 // FindContractsByID returns all contracts with the specified ids from the Sia Central explorer
-func (a *APIClient) FindContractsByID(ids ...string) (contracts []StorageContract, err error) {
+func (a *APIClient) FindContractsByID(ids ...ContractID) (contracts []StorageContract, err error) {
 	var resp batchContractsResp
 
 	if len(ids) > 10000 {
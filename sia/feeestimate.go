@@ -0,0 +1,179 @@
+package sia
+
+import (
+	"fmt"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/types"
+)
+
+var (
+	// signatureSize is the encoded size of a transaction signature covering
+	// the whole transaction
+	signatureSize = types.Transaction{
+		TransactionSignatures: []types.TransactionSignature{{
+			CoveredFields: types.FullCoveredFields,
+			Signature:     make([]byte, crypto.SignatureSize),
+		}},
+	}.MarshalSiaSize() - types.Transaction{}.MarshalSiaSize()
+
+	// standardUnlockConditions are the unlock conditions of a standard
+	// single signature address, used to estimate the size of inputs
+	standardUnlockConditions = types.UnlockConditions{
+		PublicKeys:         []types.SiaPublicKey{types.Ed25519PublicKey(crypto.PublicKey{})},
+		SignaturesRequired: 1,
+	}
+
+	// placeholderValue is used in place of unknown output values and fees
+	// when estimating the size of a transaction
+	placeholderValue = types.SiacoinPrecision.Mul64(1e6)
+)
+
+type (
+	// FeeRates fees per byte for transactions confirmed slowly, normally or
+	// quickly
+	FeeRates struct {
+		Slow   types.Currency `json:"slow"`
+		Normal types.Currency `json:"normal"`
+		Fast   types.Currency `json:"fast"`
+	}
+
+	// FeeEstimate the total fee in hastings for each confirmation speed of a
+	// transaction with the estimated signed size
+	FeeEstimate struct {
+		Size   int            `json:"size"`
+		Rates  FeeRates       `json:"rates"`
+		Slow   types.Currency `json:"slow"`
+		Normal types.Currency `json:"normal"`
+		Fast   types.Currency `json:"fast"`
+	}
+)
+
+// Estimate returns the fees of a transaction of the size
+func (fr FeeRates) Estimate(size int) FeeEstimate {
+	return FeeEstimate{
+		Size:   size,
+		Rates:  fr,
+		Slow:   fr.Slow.Mul64(uint64(size)),
+		Normal: fr.Normal.Mul64(uint64(size)),
+		Fast:   fr.Fast.Mul64(uint64(size)),
+	}
+}
+
+// FeeRatesFromMarket calculates fee rates from the fees paid by recently
+// confirmed transactions, bounded by the network's minimum and maximum
+// recommended fees. The 25th, 50th and 90th percentiles are used for slow,
+// normal and fast rates. If the market has no samples the recommended fees
+// are used instead.
+func FeeRatesFromMarket(fm FeeMarket, min, max types.Currency) (fr FeeRates) {
+	if len(fm.Samples) == 0 {
+		return FeeRates{
+			Slow:   min,
+			Normal: min.Add(max).Div64(2),
+			Fast:   max,
+		}
+	}
+
+	clamp := func(c types.Currency) types.Currency {
+		if c.Cmp(min) < 0 {
+			return min
+		} else if max.Cmp(min) > 0 && c.Cmp(max) > 0 {
+			return max
+		}
+		return c
+	}
+
+	fr.Slow = clamp(fm.P25)
+	fr.Normal = clamp(fm.P50)
+	fr.Fast = clamp(fm.P90)
+	return
+}
+
+// EstimateSignedSize estimates the encoded size of the transaction once it has
+// been signed. Each input is assumed to need its required number of
+// signatures, each covering the whole transaction, minus any signatures
+// already present. A miner fee is added if the transaction does not have
+// one.
+func EstimateSignedSize(txn types.Transaction) int {
+	size := txn.MarshalSiaSize()
+
+	if len(txn.MinerFees) == 0 {
+		size += types.Transaction{MinerFees: []types.Currency{placeholderValue}}.MarshalSiaSize() - types.Transaction{}.MarshalSiaSize()
+	}
+
+	signed := make(map[crypto.Hash]uint64)
+	for _, sig := range txn.TransactionSignatures {
+		signed[sig.ParentID]++
+	}
+
+	required := func(parentID crypto.Hash, uc types.UnlockConditions) {
+		n := uc.SignaturesRequired
+		if n > signed[parentID] {
+			size += int(n-signed[parentID]) * signatureSize
+		}
+	}
+
+	for _, sci := range txn.SiacoinInputs {
+		required(crypto.Hash(sci.ParentID), sci.UnlockConditions)
+	}
+
+	for _, sfi := range txn.SiafundInputs {
+		required(crypto.Hash(sfi.ParentID), sfi.UnlockConditions)
+	}
+
+	for _, fcr := range txn.FileContractRevisions {
+		required(crypto.Hash(fcr.ParentID), fcr.UnlockConditions)
+	}
+
+	return size
+}
+
+// EstimateTransactionSize estimates the signed size of a transaction spending
+// the number of standard single signature inputs to the number of outputs
+func EstimateTransactionSize(inputs, outputs int) int {
+	var txn types.Transaction
+	for i := 0; i < inputs; i++ {
+		txn.SiacoinInputs = append(txn.SiacoinInputs, types.SiacoinInput{
+			UnlockConditions: standardUnlockConditions,
+		})
+	}
+
+	for i := 0; i < outputs; i++ {
+		txn.SiacoinOutputs = append(txn.SiacoinOutputs, types.SiacoinOutput{
+			Value: placeholderValue,
+		})
+	}
+
+	return EstimateSignedSize(txn)
+}
+
+// GetFeeRates calculates fee rates from the network's recommended fees and the
+// fees paid by transactions in roughly the last day of blocks
+func (a *APIClient) GetFeeRates() (fr FeeRates, err error) {
+	min, max, err := a.GetTransactionFees()
+	if err != nil {
+		err = fmt.Errorf("unable to get transaction fees: %w", err)
+		return
+	}
+
+	fm, err := a.AnalyzeFeeMarket(0)
+	if err != nil {
+		err = fmt.Errorf("unable to analyze fee market: %w", err)
+		return
+	}
+
+	fr = FeeRatesFromMarket(fm, min, max)
+	return
+}
+
+// EstimateTransactionFee estimates the slow, normal and fast fees of the
+// unsigned transaction from its estimated signed size
+func (a *APIClient) EstimateTransactionFee(txn types.Transaction) (estimate FeeEstimate, err error) {
+	fr, err := a.GetFeeRates()
+	if err != nil {
+		return
+	}
+
+	estimate = fr.Estimate(EstimateSignedSize(txn))
+	return
+}
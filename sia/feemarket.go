@@ -0,0 +1,205 @@
+package sia
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// feeMarketBlocks is the default number of recent blocks sampled when
+	// analyzing the fee market, roughly one day
+	feeMarketBlocks = 144
+)
+
+var (
+	// revisionUnlockConditions have the same encoded size as the renter and
+	// host 2-of-2 unlock conditions of a contract revision, which the API
+	// does not return
+	revisionUnlockConditions = types.UnlockConditions{
+		PublicKeys: []types.SiaPublicKey{
+			types.Ed25519PublicKey(crypto.PublicKey{}),
+			types.Ed25519PublicKey(crypto.PublicKey{}),
+		},
+		SignaturesRequired: 2,
+	}
+)
+
+type (
+	// FeeSample the fee paid by a confirmed transaction
+	FeeSample struct {
+		TransactionID TransactionID  `json:"transaction_id"`
+		Height        uint64         `json:"height"`
+		Size          int            `json:"size"`
+		Fees          types.Currency `json:"fees"`
+		FeePerByte    types.Currency `json:"fee_per_byte"`
+	}
+
+	// FeeMarket the distribution of fees per byte paid by transactions
+	// confirmed in a range of blocks. Transactions without fees, such as
+	// storage proofs, are counted but not included in the distribution.
+	// Transactions that cannot be encoded locally are counted in Skipped.
+	FeeMarket struct {
+		StartHeight  uint64 `json:"start_height"`
+		EndHeight    uint64 `json:"end_height"`
+		Transactions int    `json:"transactions"`
+		ZeroFee      int    `json:"zero_fee"`
+		Skipped      int    `json:"skipped"`
+
+		Min types.Currency `json:"min"`
+		P10 types.Currency `json:"p10"`
+		P25 types.Currency `json:"p25"`
+		P50 types.Currency `json:"p50"`
+		P75 types.Currency `json:"p75"`
+		P90 types.Currency `json:"p90"`
+		Max types.Currency `json:"max"`
+
+		// Samples are sorted by fee per byte
+		Samples []FeeSample `json:"samples"`
+	}
+)
+
+// Percentile returns the fee per byte at the percentile p, between 0 and 100,
+// using the nearest-rank method. Zero is returned if there are no samples.
+func (fm FeeMarket) Percentile(p float64) types.Currency {
+	if len(fm.Samples) == 0 {
+		return types.ZeroCurrency
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(fm.Samples))))
+	if rank < 1 {
+		rank = 1
+	} else if rank > len(fm.Samples) {
+		rank = len(fm.Samples)
+	}
+
+	return fm.Samples[rank-1].FeePerByte
+}
+
+// EncodedSize returns the size of the transaction encoded by siad. The API
+// does not return the unlock conditions of contract revisions, so revisions
+// are encoded with standard 2-of-2 unlock conditions of the same size.
+func (t Transaction) EncodedSize() (int, error) {
+	revisions := t.ContractRevisions
+	t.ContractRevisions = nil
+
+	txn, err := t.SiadTransaction()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, c := range revisions {
+		fc, err := c.siadFileContract()
+		if err != nil {
+			return 0, err
+		}
+
+		txn.FileContractRevisions = append(txn.FileContractRevisions, types.FileContractRevision{
+			ParentID:              c.ID.Siad(),
+			UnlockConditions:      revisionUnlockConditions,
+			NewRevisionNumber:     fc.RevisionNumber,
+			NewFileSize:           fc.FileSize,
+			NewFileMerkleRoot:     fc.FileMerkleRoot,
+			NewWindowStart:        fc.WindowStart,
+			NewWindowEnd:          fc.WindowEnd,
+			NewValidProofOutputs:  fc.ValidProofOutputs,
+			NewMissedProofOutputs: fc.MissedProofOutputs,
+			NewUnlockHash:         fc.UnlockHash,
+		})
+	}
+
+	return txn.MarshalSiaSize(), nil
+}
+
+// AnalyzeFees calculates the fee per byte of each transaction in the blocks
+// and the distribution of fees
+func AnalyzeFees(blocks []Block) (fm FeeMarket) {
+	for i, b := range blocks {
+		if i == 0 || b.Height < fm.StartHeight {
+			fm.StartHeight = b.Height
+		}
+		if b.Height > fm.EndHeight {
+			fm.EndHeight = b.Height
+		}
+
+		for _, t := range b.Transactions {
+			fm.Transactions++
+
+			fees := t.totalFees()
+			if fees.IsZero() {
+				fm.ZeroFee++
+				continue
+			}
+
+			size, err := t.EncodedSize()
+			if err != nil || size == 0 {
+				fm.Skipped++
+				continue
+			}
+
+			fm.Samples = append(fm.Samples, FeeSample{
+				TransactionID: t.ID,
+				Height:        b.Height,
+				Size:          size,
+				Fees:          fees,
+				FeePerByte:    fees.Div64(uint64(size)),
+			})
+		}
+	}
+
+	sort.SliceStable(fm.Samples, func(i, j int) bool {
+		return fm.Samples[i].FeePerByte.Cmp(fm.Samples[j].FeePerByte) < 0
+	})
+
+	if len(fm.Samples) == 0 {
+		return
+	}
+
+	fm.Min = fm.Samples[0].FeePerByte
+	fm.P10 = fm.Percentile(10)
+	fm.P25 = fm.Percentile(25)
+	fm.P50 = fm.Percentile(50)
+	fm.P75 = fm.Percentile(75)
+	fm.P90 = fm.Percentile(90)
+	fm.Max = fm.Samples[len(fm.Samples)-1].FeePerByte
+	return
+}
+
+// AnalyzeFeeMarket samples the most recent blocks from the Sia Central
+// explorer and calculates the distribution of fees per byte paid by their
+// transactions. If n is zero, roughly one day of blocks is sampled.
+func (a *APIClient) AnalyzeFeeMarket(n uint64) (fm FeeMarket, err error) {
+	if n == 0 {
+		n = feeMarketBlocks
+	}
+
+	index, err := a.GetChainIndex()
+	if err != nil {
+		err = fmt.Errorf("unable to get chain index: %w", err)
+		return
+	} else if index.Height == 0 {
+		err = errors.New("chain has no blocks to sample")
+		return
+	}
+
+	start := uint64(1)
+	if index.Height >= n {
+		start = index.Height - n + 1
+	}
+
+	var blocks []Block
+	err = a.IterateBlocks(start, index.Height, func(b Block) error {
+		blocks = append(blocks, b)
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	fm = AnalyzeFees(blocks)
+	return
+}
@@ -0,0 +1,89 @@
+package sia
+
+import (
+	"testing"
+
+	"go.sia.tech/siad/types"
+)
+
+func TestFeeMarketPercentile(t *testing.T) {
+	fm := FeeMarket{}
+	if !fm.Percentile(50).IsZero() {
+		t.Fatal("expected zero without samples")
+	}
+
+	for i := 1; i <= 10; i++ {
+		fm.Samples = append(fm.Samples, FeeSample{FeePerByte: types.NewCurrency64(uint64(i))})
+	}
+
+	tests := []struct {
+		p        float64
+		expected uint64
+	}{
+		{0, 1},
+		{5, 1},
+		{10, 1},
+		{11, 2},
+		{25, 3},
+		{50, 5},
+		{51, 6},
+		{90, 9},
+		{99, 10},
+		{100, 10},
+		{150, 10},
+	}
+
+	for _, tt := range tests {
+		if fee := fm.Percentile(tt.p); !fee.Equals64(tt.expected) {
+			t.Fatalf("p%v: expected %v, got %v", tt.p, tt.expected, fee)
+		}
+	}
+}
+
+func TestAnalyzeFees(t *testing.T) {
+	feeTxn := func(fee uint64) Transaction {
+		return testAPITransaction(types.Transaction{
+			MinerFees: []types.Currency{types.SiacoinPrecision.Mul64(fee)},
+		})
+	}
+	unencodable := Transaction{
+		ID:               TransactionID{1},
+		Fees:             types.SiacoinPrecision,
+		StorageContracts: []StorageContract{{MerkleRoot: "not a hash"}},
+	}
+
+	blocks := []Block{
+		{Height: 12, Transactions: []Transaction{feeTxn(3), {ID: TransactionID{2}}}},
+		{Height: 10, Transactions: []Transaction{feeTxn(1), unencodable, feeTxn(2)}},
+		{Height: 11},
+	}
+
+	fm := AnalyzeFees(blocks)
+	switch {
+	case fm.StartHeight != 10 || fm.EndHeight != 12:
+		t.Fatalf("expected heights 10 to 12, got %v to %v", fm.StartHeight, fm.EndHeight)
+	case fm.Transactions != 5:
+		t.Fatalf("expected 5 transactions, got %v", fm.Transactions)
+	case fm.ZeroFee != 1:
+		t.Fatalf("expected 1 zero fee transaction, got %v", fm.ZeroFee)
+	case fm.Skipped != 1:
+		t.Fatalf("expected 1 skipped transaction, got %v", fm.Skipped)
+	case len(fm.Samples) != 3:
+		t.Fatalf("expected 3 samples, got %v", len(fm.Samples))
+	}
+
+	for i, s := range fm.Samples {
+		expected := types.SiacoinPrecision.Mul64(uint64(i + 1))
+		if !s.Fees.Equals(expected) {
+			t.Fatalf("sample %v: expected fees %v, got %v", i, expected, s.Fees)
+		} else if !s.FeePerByte.Equals(expected.Div64(uint64(s.Size))) {
+			t.Fatalf("sample %v: unexpected fee per byte %v", i, s.FeePerByte)
+		}
+	}
+
+	if !fm.Min.Equals(fm.Samples[0].FeePerByte) || !fm.Max.Equals(fm.Samples[2].FeePerByte) {
+		t.Fatal("unexpected min or max")
+	} else if !fm.P50.Equals(fm.Samples[1].FeePerByte) || !fm.P10.Equals(fm.Min) || !fm.P90.Equals(fm.Max) {
+		t.Fatal("unexpected percentiles")
+	}
+}
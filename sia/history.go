@@ -0,0 +1,120 @@
+package sia
+
+const (
+	// historyPageSize is the default number of transactions requested per
+	// page when iterating over transaction history
+	historyPageSize = 100
+)
+
+type (
+	// TransactionHistoryIterator pages through the confirmed transaction
+	// history of an address or set of addresses. Transactions returned on
+	// more than one page, such as when new transactions shift the pages
+	// during iteration, are only returned once.
+	TransactionHistoryIterator struct {
+		fetch func(limit, page int) (GetTransactionsResp, error)
+		limit int
+		page  int
+
+		seen    map[TransactionID]bool
+		buf     []Transaction
+		current Transaction
+		done    bool
+		err     error
+	}
+)
+
+// newTransactionHistoryIterator creates an iterator that retrieves pages with
+// fetch
+func newTransactionHistoryIterator(limit int, fetch func(limit, page int) (GetTransactionsResp, error)) *TransactionHistoryIterator {
+	if limit <= 0 {
+		limit = historyPageSize
+	}
+
+	return &TransactionHistoryIterator{
+		fetch: fetch,
+		limit: limit,
+		seen:  make(map[TransactionID]bool),
+	}
+}
+
+// AddressHistory returns an iterator over the confirmed transactions of the
+// address, requesting limit transactions per page. If limit is zero a default
+// page size is used.
+func (a *APIClient) AddressHistory(address UnlockHash, limit int) *TransactionHistoryIterator {
+	return newTransactionHistoryIterator(limit, func(limit, page int) (GetTransactionsResp, error) {
+		return a.GetAddressBalance(limit, page, address)
+	})
+}
+
+// AddressSetHistory returns an iterator over the confirmed transactions of
+// the addresses, requesting limit transactions per page. If limit is zero a
+// default page size is used.
+func (a *APIClient) AddressSetHistory(addresses []UnlockHash, limit int) *TransactionHistoryIterator {
+	return newTransactionHistoryIterator(limit, func(limit, page int) (GetTransactionsResp, error) {
+		return a.FindAddressBalance(limit, page, addresses)
+	})
+}
+
+// fill requests the next page of transactions
+func (it *TransactionHistoryIterator) fill() {
+	resp, err := it.fetch(it.limit, it.page)
+	if err != nil {
+		it.err = err
+		return
+	}
+	it.page++
+
+	for _, txn := range resp.Transactions {
+		if it.seen[txn.ID] {
+			continue
+		}
+
+		it.seen[txn.ID] = true
+		it.buf = append(it.buf, txn)
+	}
+
+	// a short page is the last page. A page with only duplicates means the
+	// API is not paging and iterating further would never finish.
+	if len(resp.Transactions) < it.limit || len(it.buf) == 0 {
+		it.done = true
+	}
+}
+
+// Next advances the iterator to the next transaction, returning false when
+// the history is exhausted or an error occurs
+func (it *TransactionHistoryIterator) Next() bool {
+	for len(it.buf) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+
+		it.fill()
+	}
+
+	it.current, it.buf = it.buf[0], it.buf[1:]
+	return true
+}
+
+// Transaction returns the current transaction
+func (it *TransactionHistoryIterator) Transaction() Transaction {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *TransactionHistoryIterator) Err() error {
+	return it.err
+}
+
+// All returns the remaining transactions in the history
+func (it *TransactionHistoryIterator) All() (transactions []Transaction, err error) {
+	for it.Next() {
+		transactions = append(transactions, it.Transaction())
+	}
+
+	if it.err != nil {
+		return nil, it.err
+	}
+
+	return
+}
@@ -0,0 +1,360 @@
+package sia
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/types"
+)
+
+type (
+	// BlockID the id of a block. It can be converted to and from a siad
+	// types.BlockID with a type conversion.
+	BlockID types.BlockID
+
+	// TransactionID the id of a transaction. It can be converted to and from a
+	// siad types.TransactionID with a type conversion.
+	TransactionID types.TransactionID
+
+	// OutputID the id of a siacoin or siafund output. It can be converted to
+	// and from a siad types.SiacoinOutputID or types.SiafundOutputID with a
+	// type conversion.
+	OutputID types.OutputID
+
+	// ContractID the id of a storage contract. It can be converted to and from
+	// a siad types.FileContractID with a type conversion.
+	ContractID types.FileContractID
+
+	// SignatureParentID the id of the siacoin output, siafund output or
+	// storage contract a transaction signature authorizes. It can be
+	// converted to and from a siad crypto.Hash with a type conversion.
+	SignatureParentID crypto.Hash
+
+	// UnlockHash the hash of a set of unlock conditions, also known as an
+	// address. It can be converted to and from a siad types.UnlockHash with a
+	// type conversion.
+	UnlockHash types.UnlockHash
+
+	// PublicKey a public key prefixed by its algorithm. It can be converted to
+	// and from a siad types.SiaPublicKey with a type conversion.
+	PublicKey types.SiaPublicKey
+)
+
+// parseHashString decodes a hex encoded hash
+func parseHashString(s, name string) (h [32]byte, err error) {
+	if len(s) != crypto.HashSize*2 {
+		err = fmt.Errorf("%s has wrong length %d", name, len(s))
+		return
+	}
+
+	if _, err = hex.Decode(h[:], []byte(s)); err != nil {
+		err = fmt.Errorf("unable to decode %s: %w", name, err)
+	}
+	return
+}
+
+// marshalHash encodes a hash as a hex JSON string. The API uses an empty
+// string for ids that are not set, so a zero hash is encoded as an empty
+// string.
+func marshalHash(h [32]byte) ([]byte, error) {
+	if h == ([32]byte{}) {
+		return json.Marshal("")
+	}
+
+	return json.Marshal(hex.EncodeToString(h[:]))
+}
+
+// unmarshalHash decodes a hex JSON string into a hash. An empty string decodes
+// to the zero hash.
+func unmarshalHash(b []byte, h *[32]byte, name string) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("unable to unmarshal %s: %w", name, err)
+	} else if len(s) == 0 {
+		*h = [32]byte{}
+		return nil
+	}
+
+	parsed, err := parseHashString(s, name)
+	if err != nil {
+		return err
+	}
+
+	*h = parsed
+	return nil
+}
+
+// ParseBlockID parses a hex encoded block id
+func ParseBlockID(s string) (BlockID, error) {
+	h, err := parseHashString(s, "block id")
+	return BlockID(h), err
+}
+
+// IsZero returns true if the id is not set
+func (id BlockID) IsZero() bool {
+	return id == BlockID{}
+}
+
+// String returns the id as a hex string
+func (id BlockID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// Siad returns the id as a siad block id
+func (id BlockID) Siad() types.BlockID {
+	return types.BlockID(id)
+}
+
+// MarshalJSON implements json.Marshaler
+func (id BlockID) MarshalJSON() ([]byte, error) {
+	return marshalHash(id)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (id *BlockID) UnmarshalJSON(b []byte) error {
+	return unmarshalHash(b, (*[32]byte)(id), "block id")
+}
+
+// ParseTransactionID parses a hex encoded transaction id
+func ParseTransactionID(s string) (TransactionID, error) {
+	h, err := parseHashString(s, "transaction id")
+	return TransactionID(h), err
+}
+
+// IsZero returns true if the id is not set
+func (id TransactionID) IsZero() bool {
+	return id == TransactionID{}
+}
+
+// String returns the id as a hex string
+func (id TransactionID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// Siad returns the id as a siad transaction id
+func (id TransactionID) Siad() types.TransactionID {
+	return types.TransactionID(id)
+}
+
+// MarshalJSON implements json.Marshaler
+func (id TransactionID) MarshalJSON() ([]byte, error) {
+	return marshalHash(id)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (id *TransactionID) UnmarshalJSON(b []byte) error {
+	return unmarshalHash(b, (*[32]byte)(id), "transaction id")
+}
+
+// ParseOutputID parses a hex encoded output id
+func ParseOutputID(s string) (OutputID, error) {
+	h, err := parseHashString(s, "output id")
+	return OutputID(h), err
+}
+
+// IsZero returns true if the id is not set
+func (id OutputID) IsZero() bool {
+	return id == OutputID{}
+}
+
+// String returns the id as a hex string
+func (id OutputID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// SiacoinOutputID returns the id as a siad siacoin output id
+func (id OutputID) SiacoinOutputID() types.SiacoinOutputID {
+	return types.SiacoinOutputID(id)
+}
+
+// SiafundOutputID returns the id as a siad siafund output id
+func (id OutputID) SiafundOutputID() types.SiafundOutputID {
+	return types.SiafundOutputID(id)
+}
+
+// MarshalJSON implements json.Marshaler
+func (id OutputID) MarshalJSON() ([]byte, error) {
+	return marshalHash(id)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (id *OutputID) UnmarshalJSON(b []byte) error {
+	return unmarshalHash(b, (*[32]byte)(id), "output id")
+}
+
+// ParseContractID parses a hex encoded contract id
+func ParseContractID(s string) (ContractID, error) {
+	h, err := parseHashString(s, "contract id")
+	return ContractID(h), err
+}
+
+// IsZero returns true if the id is not set
+func (id ContractID) IsZero() bool {
+	return id == ContractID{}
+}
+
+// String returns the id as a hex string
+func (id ContractID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// Siad returns the id as a siad file contract id
+func (id ContractID) Siad() types.FileContractID {
+	return types.FileContractID(id)
+}
+
+// MarshalJSON implements json.Marshaler
+func (id ContractID) MarshalJSON() ([]byte, error) {
+	return marshalHash(id)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (id *ContractID) UnmarshalJSON(b []byte) error {
+	return unmarshalHash(b, (*[32]byte)(id), "contract id")
+}
+
+// ParseSignatureParentID parses a hex encoded signature parent id
+func ParseSignatureParentID(s string) (SignatureParentID, error) {
+	h, err := parseHashString(s, "signature parent id")
+	return SignatureParentID(h), err
+}
+
+// IsZero returns true if the id is not set
+func (id SignatureParentID) IsZero() bool {
+	return id == SignatureParentID{}
+}
+
+// String returns the id as a hex string
+func (id SignatureParentID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// Siad returns the id as the crypto.Hash used by siad transaction signatures
+func (id SignatureParentID) Siad() crypto.Hash {
+	return crypto.Hash(id)
+}
+
+// MarshalJSON implements json.Marshaler
+func (id SignatureParentID) MarshalJSON() ([]byte, error) {
+	return marshalHash(id)
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (id *SignatureParentID) UnmarshalJSON(b []byte) error {
+	return unmarshalHash(b, (*[32]byte)(id), "signature parent id")
+}
+
+// ParseUnlockHash parses a hex encoded unlock hash including its checksum
+func ParseUnlockHash(s string) (uh UnlockHash, err error) {
+	err = (*types.UnlockHash)(&uh).LoadString(s)
+	return
+}
+
+// IsZero returns true if the unlock hash is not set
+func (uh UnlockHash) IsZero() bool {
+	return uh == UnlockHash{}
+}
+
+// String returns the unlock hash as a hex string including its checksum
+func (uh UnlockHash) String() string {
+	return types.UnlockHash(uh).String()
+}
+
+// Siad returns the unlock hash as a siad unlock hash
+func (uh UnlockHash) Siad() types.UnlockHash {
+	return types.UnlockHash(uh)
+}
+
+// MarshalJSON implements json.Marshaler
+func (uh UnlockHash) MarshalJSON() ([]byte, error) {
+	if uh.IsZero() {
+		return json.Marshal("")
+	}
+
+	return json.Marshal(uh.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (uh *UnlockHash) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("unable to unmarshal unlock hash: %w", err)
+	} else if len(s) == 0 {
+		*uh = UnlockHash{}
+		return nil
+	}
+
+	parsed, err := ParseUnlockHash(s)
+	if err != nil {
+		return fmt.Errorf("unable to parse unlock hash %s: %w", s, err)
+	}
+
+	*uh = parsed
+	return nil
+}
+
+// ParsePublicKey parses a public key in the form algorithm:hex
+func ParsePublicKey(s string) (pk PublicKey, err error) {
+	var spk types.SiaPublicKey
+
+	if err = spk.LoadString(s); err != nil {
+		return
+	} else if spk.Algorithm == types.SignatureEd25519 && len(spk.Key) != crypto.PublicKeySize {
+		err = errors.New("ed25519 public key has wrong length")
+		return
+	}
+
+	pk = PublicKey(spk)
+	return
+}
+
+// IsZero returns true if the public key is not set
+func (pk PublicKey) IsZero() bool {
+	return len(pk.Key) == 0 && pk.Algorithm == types.Specifier{}
+}
+
+// Equals returns true if the public keys are the same
+func (pk PublicKey) Equals(other PublicKey) bool {
+	return types.SiaPublicKey(pk).Equals(types.SiaPublicKey(other))
+}
+
+// String returns the public key in the form algorithm:hex
+func (pk PublicKey) String() string {
+	return types.SiaPublicKey(pk).String()
+}
+
+// Siad returns the public key as a siad public key
+func (pk PublicKey) Siad() types.SiaPublicKey {
+	return types.SiaPublicKey(pk)
+}
+
+// MarshalJSON implements json.Marshaler
+func (pk PublicKey) MarshalJSON() ([]byte, error) {
+	if pk.IsZero() {
+		return json.Marshal("")
+	}
+
+	return json.Marshal(pk.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (pk *PublicKey) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("unable to unmarshal public key: %w", err)
+	} else if len(s) == 0 {
+		*pk = PublicKey{}
+		return nil
+	}
+
+	parsed, err := ParsePublicKey(s)
+	if err != nil {
+		return fmt.Errorf("unable to parse public key %s: %w", s, err)
+	}
+
+	*pk = parsed
+	return nil
+}
@@ -0,0 +1,359 @@
+package sia
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"gitlab.com/NebulousLabs/fastrand"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	// keystoreVersion is the version of the encrypted keystore format
+	keystoreVersion = 1
+
+	// argon2 parameters used to derive the keystore's encryption key
+	keystoreArgonTime    = 3
+	keystoreArgonMemory  = 64 * 1024
+	keystoreArgonThreads = 4
+	keystoreSaltSize     = 32
+
+	// keystoreMaxArgonMemory and keystoreMaxArgonTime are the maximum argon2
+	// memory in KiB and passes accepted when loading a keystore, so a crafted
+	// file cannot force a huge allocation or stall key derivation
+	keystoreMaxArgonMemory = 1024 * 1024
+	keystoreMaxArgonTime   = 64
+)
+
+// ErrWrongPassword is returned when an encrypted keystore cannot be decrypted
+var ErrWrongPassword = errors.New("incorrect password or corrupted keystore")
+
+type (
+	// keystoreSeed a seed and the number of keys derived from it
+	keystoreSeed struct {
+		Seed  modules.Seed `json:"seed"`
+		Count uint64       `json:"count"`
+	}
+
+	// keystoreContents the plaintext contents of an encrypted keystore
+	keystoreContents struct {
+		Seeds []keystoreSeed     `json:"seeds"`
+		Keys  []crypto.SecretKey `json:"keys"`
+	}
+
+	// encryptedKeystore the format of a keystore encrypted at rest. The key is
+	// derived from the password with argon2id and the contents are encrypted
+	// with XChaCha20-Poly1305.
+	encryptedKeystore struct {
+		Version    int    `json:"version"`
+		Time       uint32 `json:"time"`
+		Memory     uint32 `json:"memory"`
+		Threads    uint8  `json:"threads"`
+		Salt       []byte `json:"salt"`
+		Nonce      []byte `json:"nonce"`
+		Ciphertext []byte `json:"ciphertext"`
+	}
+
+	// Keystore holds ed25519 keys derived from siad seeds or added directly
+	// and signs the inputs of transactions spending from them
+	Keystore struct {
+		mu       sync.Mutex
+		contents keystoreContents
+		// keys maps the string form of a public key to its secret key
+		keys map[string]crypto.SecretKey
+		// conditions maps standard addresses to their unlock conditions
+		conditions map[UnlockHash]types.UnlockConditions
+	}
+)
+
+// NewKeystore creates a new empty keystore
+func NewKeystore() *Keystore {
+	return &Keystore{
+		keys:       make(map[string]crypto.SecretKey),
+		conditions: make(map[UnlockHash]types.UnlockConditions),
+	}
+}
+
+// addKey adds the secret key and its standard unlock conditions
+func (ks *Keystore) addKey(sk crypto.SecretKey) UnlockHash {
+	pk := types.Ed25519PublicKey(sk.PublicKey())
+	uc := types.UnlockConditions{
+		PublicKeys:         []types.SiaPublicKey{pk},
+		SignaturesRequired: 1,
+	}
+	addr := UnlockHash(uc.UnlockHash())
+
+	ks.keys[pk.String()] = sk
+	ks.conditions[addr] = uc
+	return addr
+}
+
+// AddSeed derives the first count keys of the seed and returns their
+// addresses. Adding a seed again with a larger count derives more keys.
+func (ks *Keystore) AddSeed(seed modules.Seed, count uint64) (addresses []UnlockHash) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	var entry *keystoreSeed
+	for i := range ks.contents.Seeds {
+		if ks.contents.Seeds[i].Seed == seed {
+			entry = &ks.contents.Seeds[i]
+			break
+		}
+	}
+
+	if entry == nil {
+		ks.contents.Seeds = append(ks.contents.Seeds, keystoreSeed{Seed: seed})
+		entry = &ks.contents.Seeds[len(ks.contents.Seeds)-1]
+	}
+
+	for i := uint64(0); i < count; i++ {
+		sk, _ := SeedKey(seed, i)
+		addresses = append(addresses, ks.addKey(sk))
+	}
+
+	if count > entry.Count {
+		entry.Count = count
+	}
+	return
+}
+
+// AddKey adds a raw ed25519 secret key and returns its standard address
+func (ks *Keystore) AddKey(sk crypto.SecretKey) UnlockHash {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	pk := types.Ed25519PublicKey(sk.PublicKey())
+	if _, exists := ks.keys[pk.String()]; !exists {
+		ks.contents.Keys = append(ks.contents.Keys, sk)
+	}
+
+	return ks.addKey(sk)
+}
+
+// Addresses returns the standard single signature addresses of every key in
+// the keystore
+func (ks *Keystore) Addresses() (addresses []UnlockHash) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	return ks.addressList()
+}
+
+// UnlockConditions returns the unlock conditions of every standard address
+// in the keystore, for use with BuildOptions
+func (ks *Keystore) UnlockConditions() (conditions []types.UnlockConditions) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	for _, addr := range ks.addressList() {
+		conditions = append(conditions, ks.conditions[addr])
+	}
+	return
+}
+
+// addressList returns the sorted standard addresses
+func (ks *Keystore) addressList() (addresses []UnlockHash) {
+	for addr := range ks.conditions {
+		addresses = append(addresses, addr)
+	}
+
+	sortUnlockHashes(addresses)
+	return
+}
+
+// SignTransaction signs the inputs of the transaction spending from keys in
+// the keystore. If toSign is empty every siacoin input, siafund input and
+// contract revision the keystore holds keys for is signed; otherwise only the
+// inputs with the parent ids in toSign are signed and an error is returned if
+// any of them cannot be. Each signature covers the whole transaction, so the
+// transaction must not be modified after signing. The height determines the
+// replay protection and must be at least the timelock of each input.
+func (ks *Keystore) SignTransaction(txn *types.Transaction, height uint64, toSign ...crypto.Hash) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	type input struct {
+		parentID crypto.Hash
+		uc       types.UnlockConditions
+	}
+
+	var inputs []input
+	for _, sci := range txn.SiacoinInputs {
+		inputs = append(inputs, input{crypto.Hash(sci.ParentID), sci.UnlockConditions})
+	}
+	for _, sfi := range txn.SiafundInputs {
+		inputs = append(inputs, input{crypto.Hash(sfi.ParentID), sfi.UnlockConditions})
+	}
+	for _, fcr := range txn.FileContractRevisions {
+		inputs = append(inputs, input{crypto.Hash(fcr.ParentID), fcr.UnlockConditions})
+	}
+
+	requested := make(map[crypto.Hash]bool, len(toSign))
+	for _, id := range toSign {
+		requested[id] = true
+	}
+
+	// signatures already on the transaction count towards the required
+	// signatures and their keys cannot be used again
+	existing := make(map[crypto.Hash]map[uint64]bool)
+	for _, sig := range txn.TransactionSignatures {
+		if existing[sig.ParentID] == nil {
+			existing[sig.ParentID] = make(map[uint64]bool)
+		}
+		existing[sig.ParentID][sig.PublicKeyIndex] = true
+	}
+
+	for id := range requested {
+		found := false
+		for _, in := range inputs {
+			if in.parentID == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("transaction has no input %s", id)
+		}
+	}
+
+	var signers []crypto.SecretKey
+	start := len(txn.TransactionSignatures)
+	for _, in := range inputs {
+		if len(requested) != 0 && !requested[in.parentID] {
+			continue
+		}
+
+		if uint64(in.uc.Timelock) > height {
+			txn.TransactionSignatures = txn.TransactionSignatures[:start]
+			return fmt.Errorf("input %s is timelocked until height %d", in.parentID, in.uc.Timelock)
+		}
+
+		signed := uint64(len(existing[in.parentID]))
+		for i, pk := range in.uc.PublicKeys {
+			if signed >= in.uc.SignaturesRequired {
+				break
+			} else if existing[in.parentID][uint64(i)] {
+				continue
+			}
+
+			sk, ok := ks.keys[pk.String()]
+			if !ok {
+				continue
+			}
+
+			txn.TransactionSignatures = append(txn.TransactionSignatures, types.TransactionSignature{
+				ParentID:       in.parentID,
+				PublicKeyIndex: uint64(i),
+				CoveredFields:  types.FullCoveredFields,
+			})
+			signers = append(signers, sk)
+			signed++
+		}
+
+		if len(requested) != 0 && signed < in.uc.SignaturesRequired {
+			txn.TransactionSignatures = txn.TransactionSignatures[:start]
+			return fmt.Errorf("missing keys to sign input %s: %d of %d signatures", in.parentID, signed, in.uc.SignaturesRequired)
+		}
+	}
+
+	if len(signers) == 0 {
+		return errors.New("no inputs could be signed by the keystore")
+	}
+
+	for i, sk := range signers {
+		index := start + i
+		sig := crypto.SignHash(txn.SigHash(index, types.BlockHeight(height)), sk)
+		txn.TransactionSignatures[index].Signature = sig[:]
+	}
+
+	return nil
+}
+
+// Save encrypts the keystore's seeds and keys with the password and writes
+// them to w
+func (ks *Keystore) Save(w io.Writer, password []byte) error {
+	ks.mu.Lock()
+	plaintext, err := json.Marshal(ks.contents)
+	ks.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("unable to encode keystore: %w", err)
+	}
+	defer fastrand.Read(plaintext)
+
+	ek := encryptedKeystore{
+		Version: keystoreVersion,
+		Time:    keystoreArgonTime,
+		Memory:  keystoreArgonMemory,
+		Threads: keystoreArgonThreads,
+		Salt:    fastrand.Bytes(keystoreSaltSize),
+		Nonce:   fastrand.Bytes(chacha20poly1305.NonceSizeX),
+	}
+
+	key := argon2.IDKey(password, ek.Salt, ek.Time, ek.Memory, ek.Threads, chacha20poly1305.KeySize)
+	defer fastrand.Read(key)
+
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return err
+	}
+
+	ek.Ciphertext = aead.Seal(nil, ek.Nonce, plaintext, nil)
+	return json.NewEncoder(w).Encode(ek)
+}
+
+// LoadKeystore reads a keystore encrypted by Save and decrypts it with the
+// password. ErrWrongPassword is returned if it cannot be decrypted.
+func LoadKeystore(r io.Reader, password []byte) (*Keystore, error) {
+	var ek encryptedKeystore
+	if err := json.NewDecoder(r).Decode(&ek); err != nil {
+		return nil, fmt.Errorf("unable to decode keystore: %w", err)
+	} else if ek.Version != keystoreVersion {
+		return nil, fmt.Errorf("unsupported keystore version %d", ek.Version)
+	} else if len(ek.Nonce) != chacha20poly1305.NonceSizeX {
+		return nil, errors.New("invalid keystore nonce")
+	} else if len(ek.Salt) != keystoreSaltSize {
+		return nil, errors.New("invalid keystore salt")
+	} else if ek.Time < 1 || ek.Threads < 1 {
+		return nil, errors.New("invalid keystore key derivation parameters")
+	} else if ek.Time > keystoreMaxArgonTime {
+		return nil, fmt.Errorf("keystore key derivation time %d exceeds maximum of %d", ek.Time, keystoreMaxArgonTime)
+	} else if ek.Memory > keystoreMaxArgonMemory {
+		return nil, fmt.Errorf("keystore key derivation memory %d KiB exceeds maximum of %d KiB", ek.Memory, keystoreMaxArgonMemory)
+	}
+
+	key := argon2.IDKey(password, ek.Salt, ek.Time, ek.Memory, ek.Threads, chacha20poly1305.KeySize)
+	defer fastrand.Read(key)
+
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, ek.Nonce, ek.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongPassword
+	}
+	defer fastrand.Read(plaintext)
+
+	var contents keystoreContents
+	if err := json.Unmarshal(plaintext, &contents); err != nil {
+		return nil, fmt.Errorf("unable to decode keystore contents: %w", err)
+	}
+
+	ks := NewKeystore()
+	for _, s := range contents.Seeds {
+		ks.AddSeed(s.Seed, s.Count)
+	}
+	for _, sk := range contents.Keys {
+		ks.AddKey(sk)
+	}
+	return ks, nil
+}
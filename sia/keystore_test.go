@@ -0,0 +1,119 @@
+package sia
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"gitlab.com/NebulousLabs/fastrand"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+func TestKeystoreSaveLoad(t *testing.T) {
+	var seed modules.Seed
+	fastrand.Read(seed[:])
+
+	ks := NewKeystore()
+	ks.AddSeed(seed, 5)
+	sk, _ := crypto.GenerateKeyPair()
+	ks.AddKey(sk)
+
+	var buf bytes.Buffer
+	if err := ks.Save(&buf, []byte("password")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadKeystore(bytes.NewReader(buf.Bytes()), []byte("wrong")); !errors.Is(err, ErrWrongPassword) {
+		t.Fatalf("expected ErrWrongPassword, got %v", err)
+	}
+
+	loaded, err := LoadKeystore(bytes.NewReader(buf.Bytes()), []byte("password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, actual := ks.Addresses(), loaded.Addresses()
+	if len(actual) != 6 || len(actual) != len(expected) {
+		t.Fatalf("expected %d addresses, got %d", len(expected), len(actual))
+	}
+	for i := range expected {
+		if expected[i] != actual[i] {
+			t.Fatalf("address %d: expected %s, got %s", i, expected[i], actual[i])
+		}
+	}
+}
+
+func TestLoadKeystoreInvalidParameters(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewKeystore().Save(&buf, []byte("password")); err != nil {
+		t.Fatal(err)
+	}
+
+	var valid encryptedKeystore
+	if err := json.Unmarshal(buf.Bytes(), &valid); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*encryptedKeystore)
+	}{
+		{"zero time", func(ek *encryptedKeystore) { ek.Time = 0 }},
+		{"huge time", func(ek *encryptedKeystore) { ek.Time = 4e9 }},
+		{"zero threads", func(ek *encryptedKeystore) { ek.Threads = 0 }},
+		{"huge memory", func(ek *encryptedKeystore) { ek.Memory = 1 << 31 }},
+		{"short salt", func(ek *encryptedKeystore) { ek.Salt = ek.Salt[:8] }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ek := valid
+			tt.modify(&ek)
+
+			buf, err := json.Marshal(ek)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := LoadKeystore(bytes.NewReader(buf), []byte("password")); err == nil {
+				t.Fatal("expected invalid keystore to be rejected")
+			}
+		})
+	}
+}
+
+func TestKeystoreSignTransaction(t *testing.T) {
+	const height = 300000
+
+	ks := NewKeystore()
+	sk, _ := crypto.GenerateKeyPair()
+	ks.AddKey(sk)
+
+	txn := types.Transaction{
+		SiacoinInputs: []types.SiacoinInput{{
+			ParentID:         types.SiacoinOutputID{1},
+			UnlockConditions: ks.UnlockConditions()[0],
+		}},
+		SiacoinOutputs: []types.SiacoinOutput{{
+			Value:      types.SiacoinPrecision,
+			UnlockHash: types.UnlockHash{2},
+		}},
+		MinerFees: []types.Currency{types.NewCurrency64(1)},
+	}
+
+	if err := ks.SignTransaction(&txn, height); err != nil {
+		t.Fatal(err)
+	} else if err := txn.StandaloneValid(height); err != nil {
+		t.Fatalf("signed transaction is invalid: %s", err)
+	} else if err := VerifyTransactionSignatures(testAPITransaction(txn), height); err != nil {
+		t.Fatalf("signed transaction failed verification: %s", err)
+	}
+
+	// every input is already signed
+	if err := ks.SignTransaction(&txn, height); err == nil {
+		t.Fatal("expected signing a signed transaction to fail")
+	}
+}
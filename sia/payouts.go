@@ -0,0 +1,135 @@
+package sia
+
+import (
+	"fmt"
+	"strings"
+
+	"go.sia.tech/siad/types"
+)
+
+type (
+	// PayoutReport the expected and actual payouts of a block
+	PayoutReport struct {
+		Height  uint64  `json:"height"`
+		BlockID BlockID `json:"block_id"`
+
+		Subsidy        types.Currency `json:"subsidy"`
+		Fees           types.Currency `json:"fees"`
+		ExpectedPayout types.Currency `json:"expected_payout"`
+		ActualPayout   types.Currency `json:"actual_payout"`
+
+		ExpectedFoundationSubsidy types.Currency `json:"expected_foundation_subsidy"`
+		ActualFoundationSubsidy   types.Currency `json:"actual_foundation_subsidy"`
+
+		Errors []string `json:"errors"`
+	}
+)
+
+// BlockSubsidy returns the siacoins created for the miner of the block at
+// the height, excluding fees. The genesis block has no miner payouts.
+func BlockSubsidy(height uint64) types.Currency {
+	if height == 0 {
+		return types.ZeroCurrency
+	}
+
+	return types.CalculateCoinbase(types.BlockHeight(height))
+}
+
+// FoundationSubsidy returns the Foundation subsidy paid by the block at the
+// height. Since the Foundation hardfork a subsidy is paid every month, with a
+// larger initial subsidy at the hardfork height.
+func FoundationSubsidy(height uint64) types.Currency {
+	h := types.BlockHeight(height)
+	switch {
+	case h < types.FoundationHardforkHeight:
+		return types.ZeroCurrency
+	case h == types.FoundationHardforkHeight:
+		return types.InitialFoundationSubsidy
+	case (h-types.FoundationHardforkHeight)%types.FoundationSubsidyFrequency != 0:
+		return types.ZeroCurrency
+	}
+
+	return types.FoundationSubsidyPerBlock.Mul64(uint64(types.FoundationSubsidyFrequency))
+}
+
+// Valid returns true if the block's payouts match the expected payouts
+func (pr PayoutReport) Valid() bool {
+	return len(pr.Errors) == 0
+}
+
+// Err returns an error describing the payout mismatches, nil if the payouts
+// are valid
+func (pr PayoutReport) Err() error {
+	if pr.Valid() {
+		return nil
+	}
+
+	return fmt.Errorf("block %d has invalid payouts: %s", pr.Height, strings.Join(pr.Errors, ", "))
+}
+
+// VerifyMinerPayouts checks that the block's miner payouts sum to the block
+// subsidy plus the miner fees of its transactions and that the Foundation
+// subsidy matches the subsidy due at the block's height.
+func VerifyMinerPayouts(b Block) (report PayoutReport) {
+	report.Height = b.Height
+	report.BlockID = b.ID
+	report.Subsidy = BlockSubsidy(b.Height)
+	report.ExpectedFoundationSubsidy = FoundationSubsidy(b.Height)
+
+	// consensus only counts miner fees, the API's fees may include other
+	// amounts such as the siafund tax
+	for _, t := range b.Transactions {
+		for _, fee := range t.MinerFees {
+			report.Fees = report.Fees.Add(fee)
+		}
+	}
+	report.ExpectedPayout = report.Subsidy.Add(report.Fees)
+
+	flag := func(format string, args ...interface{}) {
+		report.Errors = append(report.Errors, fmt.Sprintf(format, args...))
+	}
+
+	payouts := b.minerPayouts()
+	matched := make(map[OutputID]bool, len(payouts))
+	for _, o := range payouts {
+		if o.Value.IsZero() {
+			flag("miner payout %s has a zero value", o.OutputID)
+		}
+
+		matched[o.OutputID] = true
+		report.ActualPayout = report.ActualPayout.Add(o.Value)
+	}
+
+	foundationID := OutputID(b.ID.Siad().FoundationSubsidyID())
+	for _, o := range b.SiacoinOutputs {
+		switch {
+		case matched[o.OutputID]:
+		case o.OutputID == foundationID || o.Source == OutputSourceFoundationSubsidy:
+			report.ActualFoundationSubsidy = report.ActualFoundationSubsidy.Add(o.Value)
+		case o.Source.IsMinerPayout():
+			flag("miner payout %s does not have a valid miner payout id", o.OutputID)
+		}
+	}
+
+	if !report.ActualPayout.Equals(report.ExpectedPayout) {
+		flag("miner payouts total %s H, expected %s H", report.ActualPayout, report.ExpectedPayout)
+	}
+
+	if !report.ActualFoundationSubsidy.Equals(report.ExpectedFoundationSubsidy) {
+		flag("foundation subsidy is %s H, expected %s H", report.ActualFoundationSubsidy, report.ExpectedFoundationSubsidy)
+	}
+
+	return
+}
+
+// FindInvalidPayouts verifies the payouts of the blocks from start to end
+// inclusive and returns the reports of blocks with invalid payouts
+func (a *APIClient) FindInvalidPayouts(start, end uint64) (invalid []PayoutReport, err error) {
+	err = a.IterateBlocks(start, end, func(b Block) error {
+		if report := VerifyMinerPayouts(b); !report.Valid() {
+			invalid = append(invalid, report)
+		}
+		return nil
+	})
+	return
+}
@@ -0,0 +1,101 @@
+package sia
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"go.sia.tech/siad/crypto"
+)
+
+const (
+	// storage proofs of final segments shorter than a full segment were
+	// fixed by a hardfork at height 21000 and full final segments at
+	// height 100000
+	partialSegmentHardforkHeight = 21000
+	fullSegmentHardforkHeight    = 100000
+)
+
+// StorageProofSegmentIndex calculates the index of the segment a host must
+// prove for the contract. The trigger block is the block at the height before
+// the contract's proof window starts.
+func StorageProofSegmentIndex(contract StorageContract, triggerBlockID BlockID) (index uint64, err error) {
+	fileSize, err := contract.FileSize.Uint64()
+	if err != nil {
+		err = fmt.Errorf("unable to convert file size: %w", err)
+		return
+	}
+
+	seed := crypto.HashAll(triggerBlockID.Siad(), contract.ID.Siad())
+	numSegments := new(big.Int).SetUint64(crypto.CalculateLeaves(fileSize))
+	seedInt := new(big.Int).SetBytes(seed[:])
+	index = seedInt.Mod(seedInt, numSegments).Uint64()
+	return
+}
+
+// VerifyStorageProofWithTrigger verifies the storage proof against the merkle
+// root of the contract it targets using the id of the trigger block at the
+// height before the contract's proof window starts. The contract should be the
+// final revision of the contract.
+func VerifyStorageProofWithTrigger(proof StorageProof, contract StorageContract, triggerBlockID BlockID) error {
+	if proof.ContractID != contract.ID {
+		return fmt.Errorf("storage proof is for contract %s not %s", proof.ContractID, contract.ID)
+	}
+
+	index, err := StorageProofSegmentIndex(contract, triggerBlockID)
+	if err != nil {
+		return err
+	}
+
+	sp, err := proof.siadStorageProof()
+	if err != nil {
+		return err
+	}
+
+	root, err := parseHash(contract.MerkleRoot)
+	if err != nil {
+		return fmt.Errorf("unable to parse merkle root %s: %w", contract.MerkleRoot, err)
+	}
+
+	// file size was already validated when calculating the segment index
+	fileSize, _ := contract.FileSize.Uint64()
+	leaves := crypto.CalculateLeaves(fileSize)
+	segmentLen := uint64(crypto.SegmentSize)
+
+	// the final segment should only be as long as necessary to complete the
+	// file size
+	if index == leaves-1 {
+		segmentLen = fileSize % crypto.SegmentSize
+	}
+
+	switch {
+	case proof.BlockHeight < partialSegmentHardforkHeight:
+		segmentLen = crypto.SegmentSize
+	case proof.BlockHeight >= fullSegmentHardforkHeight && segmentLen == 0:
+		segmentLen = crypto.SegmentSize
+	}
+
+	// after the full segment hardfork consensus accepts any proof for an
+	// empty contract
+	if !crypto.VerifySegment(sp.Segment[:segmentLen], sp.HashSet, leaves, index, root) && (fileSize > 0 || proof.BlockHeight < fullSegmentHardforkHeight) {
+		return fmt.Errorf("storage proof for contract %s segment %d is invalid", contract.ID, index)
+	}
+
+	return nil
+}
+
+// VerifyStorageProof verifies the storage proof against the merkle root of the
+// contract it targets. The trigger block used to select the proven segment is
+// retrieved from the Sia Central explorer.
+func (a *APIClient) VerifyStorageProof(proof StorageProof, contract StorageContract) error {
+	if contract.ExpirationHeight == 0 {
+		return errors.New("contract has no proof window")
+	}
+
+	trigger, err := a.GetBlockByHeight(contract.ExpirationHeight - 1)
+	if err != nil {
+		return fmt.Errorf("unable to get trigger block: %w", err)
+	}
+
+	return VerifyStorageProofWithTrigger(proof, contract, trigger.ID)
+}
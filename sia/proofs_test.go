@@ -0,0 +1,68 @@
+package sia
+
+import (
+	"testing"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/types"
+)
+
+func TestVerifyStorageProofSegmentLength(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileSize int
+		height   uint64
+		valid    bool
+	}{
+		// before 21000 the final segment was always verified as a full
+		// segment, so proofs of partial final segments failed
+		{"partial segment before 21000", 10, 20999, false},
+		{"partial segment at 21000", 10, 21000, true},
+		{"partial segment after 100000", 10, 300000, true},
+		// between 21000 and 100000 a full final segment was verified as an
+		// empty segment
+		{"full segment before 21000", 64, 20999, true},
+		{"full segment at 21000", 64, 21000, false},
+		{"full segment at 100000", 64, 100000, true},
+		// after 100000 any proof of an empty contract is valid
+		{"empty contract before 100000", 0, 99999, false},
+		{"empty contract at 100000", 0, 100000, true},
+		{"empty contract after 100000", 0, 300000, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := make([]byte, tt.fileSize)
+			for i := range data {
+				data[i] = byte(i + 1)
+			}
+
+			contract := StorageContract{
+				ID:         ContractID{1},
+				FileSize:   types.NewCurrency64(uint64(tt.fileSize)),
+				MerkleRoot: crypto.MerkleRoot(data).String(),
+			}
+			proof := StorageProof{
+				ContractID:  contract.ID,
+				BlockHeight: tt.height,
+			}
+
+			// every file is a single segment, so the final segment is
+			// always proven
+			if len(data) != 0 {
+				base, hashSet := crypto.MerkleProof(data, 0)
+				copy(proof.Segment[:], base)
+				for _, h := range hashSet {
+					proof.Hashset = append(proof.Hashset, h.String())
+				}
+			}
+
+			err := VerifyStorageProofWithTrigger(proof, contract, BlockID{2})
+			if tt.valid && err != nil {
+				t.Fatalf("expected valid proof, got %s", err)
+			} else if !tt.valid && err == nil {
+				t.Fatal("expected invalid proof")
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package sia
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// SearchResultBlock the query matched a block id or height
+	SearchResultBlock SearchResultType = "block"
+	// SearchResultTransaction the query matched a transaction id
+	SearchResultTransaction SearchResultType = "transaction"
+	// SearchResultContract the query matched a contract id
+	SearchResultContract SearchResultType = "contract"
+	// SearchResultAddress the query matched an address
+	SearchResultAddress SearchResultType = "address"
+	// SearchResultHost the query matched a host public key or netaddress
+	SearchResultHost SearchResultType = "host"
+
+	// searchAddressLimit the number of transactions returned for an address
+	searchAddressLimit = 20
+)
+
+var (
+	// ErrNoSearchResult is returned when a search query does not match
+	// anything in the explorer
+	ErrNoSearchResult = errors.New("no results found")
+)
+
+type (
+	// SearchResultType the type of object a search query matched
+	SearchResultType string
+
+	// SearchResult the result of a search. Only the field matching the result
+	// type is set.
+	SearchResult struct {
+		Type        SearchResultType     `json:"type"`
+		Block       *Block               `json:"block,omitempty"`
+		Transaction *Transaction         `json:"transaction,omitempty"`
+		Contract    *StorageContract     `json:"contract,omitempty"`
+		Address     *GetTransactionsResp `json:"address,omitempty"`
+		Host        *HostDetails         `json:"host,omitempty"`
+	}
+)
+
+// isHex returns true if every character of s is a hex digit
+func isHex(s string) bool {
+	for _, c := range s {
+		if !(c >= '0' && c <= '9') && !(c >= 'a' && c <= 'f') && !(c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+	return len(s) > 0
+}
+
+// searchHash looks up a 64 character hex string that may be a block,
+// transaction or contract id. Block ids have leading zeros from proof of work,
+// so they are checked first when the id has them.
+func (a *APIClient) searchHash(h crypto.Hash) (result SearchResult, err error) {
+	searchBlock := func() (bool, error) {
+		blocks, err := a.FindBlocksByID(BlockID(h))
+		if err != nil || len(blocks) == 0 {
+			return false, err
+		}
+
+		result = SearchResult{Type: SearchResultBlock, Block: &blocks[0]}
+		return true, nil
+	}
+
+	searchTransaction := func() (bool, error) {
+		transactions, err := a.FindTransactionsByID(TransactionID(h))
+		if err != nil || len(transactions) == 0 {
+			return false, err
+		}
+
+		result = SearchResult{Type: SearchResultTransaction, Transaction: &transactions[0]}
+		return true, nil
+	}
+
+	searchContract := func() (bool, error) {
+		contracts, err := a.FindContractsByID(ContractID(h))
+		if err != nil || len(contracts) == 0 {
+			return false, err
+		}
+
+		result = SearchResult{Type: SearchResultContract, Contract: &contracts[0]}
+		return true, nil
+	}
+
+	searches := []func() (bool, error){searchTransaction, searchBlock, searchContract}
+	if h[0] == 0 && h[1] == 0 {
+		searches = []func() (bool, error){searchBlock, searchTransaction, searchContract}
+	}
+
+	for _, search := range searches {
+		var found bool
+
+		found, err = search()
+		if err != nil || found {
+			return
+		}
+	}
+
+	// the hash may be an ed25519 host public key without its prefix
+	return a.searchHost(types.Ed25519PublicKey(crypto.PublicKey(h)).String())
+}
+
+// searchHost looks up a host by public key or netaddress
+func (a *APIClient) searchHost(id string) (result SearchResult, err error) {
+	host, err := a.GetHost(id)
+	if err != nil {
+		err = ErrNoSearchResult
+		return
+	}
+
+	result = SearchResult{Type: SearchResultHost, Host: &host}
+	return
+}
+
+// Search classifies the query and looks it up in the Sia Central explorer. The
+// query may be a block id or height, a transaction id, a contract id, an
+// address, or a host's public key or netaddress. ErrNoSearchResult is returned
+// if nothing matches the query.
+func (a *APIClient) Search(query string) (result SearchResult, err error) {
+	query = strings.TrimSpace(query)
+
+	switch {
+	case len(query) == 0:
+		err = errors.New("empty search query")
+		return
+	case strings.HasPrefix(query, "ed25519:"):
+		if _, err = ParsePublicKey(query); err != nil {
+			return
+		}
+
+		return a.searchHost(query)
+	case isHex(query) && len(query) == crypto.HashSize*2+types.UnlockHashChecksumSize*2:
+		var address UnlockHash
+		var resp GetTransactionsResp
+
+		address, err = ParseUnlockHash(query)
+		if err != nil {
+			return
+		}
+
+		resp, err = a.GetAddressBalance(searchAddressLimit, 0, address)
+		if err != nil {
+			return
+		}
+
+		result = SearchResult{Type: SearchResultAddress, Address: &resp}
+		return
+	case isHex(query) && len(query) == crypto.HashSize*2:
+		var h crypto.Hash
+
+		if err = h.LoadString(strings.ToLower(query)); err != nil {
+			return
+		}
+
+		return a.searchHash(h)
+	}
+
+	if height, parseErr := strconv.ParseUint(query, 10, 64); parseErr == nil {
+		blocks, err := a.FindBlocksByHeight(height)
+		if err != nil {
+			return SearchResult{}, err
+		} else if len(blocks) == 0 {
+			return SearchResult{}, ErrNoSearchResult
+		}
+
+		return SearchResult{Type: SearchResultBlock, Block: &blocks[0]}, nil
+	}
+
+	if _, _, splitErr := net.SplitHostPort(query); splitErr == nil {
+		return a.searchHost(query)
+	}
+
+	err = ErrNoSearchResult
+	return
+}
@@ -0,0 +1,138 @@
+package sia
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	mnemonics "gitlab.com/NebulousLabs/entropy-mnemonics"
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/modules"
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// DefaultGapLimit is the number of consecutive unused addresses after
+	// which address discovery stops
+	DefaultGapLimit = 100
+
+	// discoveryBatchSize is the default number of addresses checked per
+	// request during address discovery
+	discoveryBatchSize = 1000
+)
+
+type (
+	// DeriveFunc derives the unlock conditions of the address at an index
+	DeriveFunc func(index uint64) types.UnlockConditions
+
+	// DiscoveredAddress a derived address that has been used on the
+	// blockchain
+	DiscoveredAddress struct {
+		Index            uint64                 `json:"index"`
+		Address          UnlockHash             `json:"address"`
+		UnlockConditions types.UnlockConditions `json:"unlock_conditions"`
+		Usage            []AddressUsageType     `json:"usage"`
+	}
+)
+
+// ParseSeed decodes an English seed phrase into a siad seed
+func ParseSeed(phrase string) (modules.Seed, error) {
+	return modules.StringToSeed(strings.Join(strings.Fields(phrase), " "), mnemonics.English)
+}
+
+// SeedKey derives the secret key and standard unlock conditions at the index
+// of the seed, the same way siad's wallet does
+func SeedKey(seed modules.Seed, index uint64) (crypto.SecretKey, types.UnlockConditions) {
+	sk, pk := crypto.GenerateKeyPairDeterministic(crypto.HashAll(seed, index))
+	return sk, types.UnlockConditions{
+		PublicKeys:         []types.SiaPublicKey{types.Ed25519PublicKey(pk)},
+		SignaturesRequired: 1,
+	}
+}
+
+// SeedDeriveFunc returns a DeriveFunc deriving the seed's addresses
+func SeedDeriveFunc(seed modules.Seed) DeriveFunc {
+	return func(index uint64) types.UnlockConditions {
+		_, uc := SeedKey(seed, index)
+		return uc
+	}
+}
+
+// DiscoverAddresses derives addresses in batches and checks which have been
+// used with the Sia Central API. Discovery stops once gap consecutive
+// addresses after the last used address are unused. If gap or batch are zero
+// the defaults are used. The used addresses are returned in index order.
+func (a *APIClient) DiscoverAddresses(derive DeriveFunc, gap, batch uint64) (used []DiscoveredAddress, err error) {
+	if derive == nil {
+		err = errors.New("derive func must not be nil")
+		return
+	}
+
+	if gap == 0 {
+		gap = DefaultGapLimit
+	}
+
+	switch {
+	case batch == 0:
+		batch = discoveryBatchSize
+	case batch > maxBatchSize:
+		batch = maxBatchSize
+	}
+
+	// unused is the index after the last used address
+	var start, unused uint64
+	for start-unused < gap {
+		derived := make(map[UnlockHash]DiscoveredAddress, batch)
+		addresses := make([]UnlockHash, 0, batch)
+		for i := start; i < start+batch; i++ {
+			uc := derive(i)
+			addr := UnlockHash(uc.UnlockHash())
+			derived[addr] = DiscoveredAddress{
+				Index:            i,
+				Address:          addr,
+				UnlockConditions: uc,
+			}
+			addresses = append(addresses, addr)
+		}
+
+		usage, err := a.FindUsedAddresses(addresses)
+		if err != nil {
+			return nil, fmt.Errorf("unable to check addresses %d-%d: %w", start, start+batch-1, err)
+		}
+
+		found := make(map[UnlockHash]*DiscoveredAddress)
+		for _, u := range usage {
+			d, exists := derived[u.Address]
+			if !exists {
+				continue
+			} else if found[u.Address] == nil {
+				found[u.Address] = &d
+			}
+
+			if u.UsageType != "" {
+				found[u.Address].Usage = append(found[u.Address].Usage, u.UsageType)
+			}
+		}
+
+		for _, d := range found {
+			used = append(used, *d)
+			if d.Index+1 > unused {
+				unused = d.Index + 1
+			}
+		}
+
+		start += batch
+	}
+
+	sort.Slice(used, func(i, j int) bool {
+		return used[i].Index < used[j].Index
+	})
+	return
+}
+
+// DiscoverSeedAddresses discovers the used addresses of the seed using the
+// default gap limit and batch size
+func (a *APIClient) DiscoverSeedAddresses(seed modules.Seed) ([]DiscoveredAddress, error) {
+	return a.DiscoverAddresses(SeedDeriveFunc(seed), 0, 0)
+}
@@ -0,0 +1,308 @@
+package sia
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// StatsHourly groups chain statistics by hour
+	StatsHourly = time.Hour
+	// StatsDaily groups chain statistics by day
+	StatsDaily = 24 * time.Hour
+
+	// blockBatchSize is the number of blocks requested at once when iterating
+	// over a range of blocks. Blocks include their transactions so batches are
+	// kept small.
+	blockBatchSize = 100
+)
+
+type (
+	// BlockStats statistics of a single block. BlockTime is the time since the
+	// previous block and may be negative since block timestamps are not
+	// strictly increasing.
+	BlockStats struct {
+		Height             uint64         `json:"height"`
+		ID                 BlockID        `json:"id"`
+		Timestamp          time.Time      `json:"timestamp"`
+		BlockTime          time.Duration  `json:"block_time"`
+		Transactions       int            `json:"transactions"`
+		Fees               types.Currency `json:"fees"`
+		ContractFormations int            `json:"contract_formations"`
+		ContractRevisions  int            `json:"contract_revisions"`
+		StorageProofs      int            `json:"storage_proofs"`
+		HostAnnouncements  int            `json:"host_announcements"`
+	}
+
+	// BucketStats statistics of the blocks with a timestamp in the bucket
+	BucketStats struct {
+		Start              time.Time      `json:"start"`
+		End                time.Time      `json:"end"`
+		Blocks             int            `json:"blocks"`
+		MinHeight          uint64         `json:"min_height"`
+		MaxHeight          uint64         `json:"max_height"`
+		AverageBlockTime   time.Duration  `json:"average_block_time"`
+		Transactions       int            `json:"transactions"`
+		Fees               types.Currency `json:"fees"`
+		ContractFormations int            `json:"contract_formations"`
+		ContractRevisions  int            `json:"contract_revisions"`
+		StorageProofs      int            `json:"storage_proofs"`
+		HostAnnouncements  int            `json:"host_announcements"`
+
+		// totalBlockTime is the sum of block times of blocks with a known
+		// previous block
+		totalBlockTime time.Duration
+		timedBlocks    int
+	}
+
+	// ChainStats per-block and bucketed statistics of a range of blocks
+	ChainStats struct {
+		Blocks  []BlockStats  `json:"blocks"`
+		Buckets []BucketStats `json:"buckets"`
+	}
+
+	// statsAggregator accumulates statistics of blocks in height order
+	statsAggregator struct {
+		bucket  time.Duration
+		prev    *Block
+		stats   ChainStats
+		buckets map[time.Time]*BucketStats
+	}
+)
+
+// totalFees returns the miner fees of the transaction
+func (t Transaction) totalFees() (fees types.Currency) {
+	if !t.Fees.IsZero() {
+		return t.Fees
+	}
+
+	for _, fee := range t.MinerFees {
+		fees = fees.Add(fee)
+	}
+	return
+}
+
+// announcements returns the host announcements in the block. The API may
+// only include them on the block's transactions.
+func (b Block) announcements() (announcements []Announcement) {
+	if len(b.HostAnnouncements) != 0 {
+		return b.HostAnnouncements
+	}
+
+	for _, t := range b.Transactions {
+		announcements = append(announcements, t.HostAnnouncements...)
+	}
+	return
+}
+
+// blockStats calculates the statistics of a block. The block time is not set.
+func blockStats(b Block) (stats BlockStats) {
+	stats.Height = b.Height
+	stats.ID = b.ID
+	stats.Timestamp = b.Timestamp
+	stats.Transactions = len(b.Transactions)
+	stats.HostAnnouncements = len(b.announcements())
+
+	for _, t := range b.Transactions {
+		stats.Fees = stats.Fees.Add(t.totalFees())
+		stats.ContractFormations += len(t.StorageContracts)
+		stats.ContractRevisions += len(t.ContractRevisions)
+		stats.StorageProofs += len(t.StorageProofs)
+	}
+
+	return
+}
+
+// add adds the block to the statistics. Blocks must be added in height order
+// for block times to be calculated.
+func (sa *statsAggregator) add(b Block) {
+	stats := blockStats(b)
+	timed := sa.prev != nil && sa.prev.Height+1 == b.Height
+	if timed {
+		stats.BlockTime = b.Timestamp.Sub(sa.prev.Timestamp)
+	}
+	sa.prev = &b
+	sa.stats.Blocks = append(sa.stats.Blocks, stats)
+
+	if sa.bucket <= 0 {
+		return
+	}
+
+	start := b.Timestamp.UTC().Truncate(sa.bucket)
+	bucket, exists := sa.buckets[start]
+	if !exists {
+		bucket = &BucketStats{
+			Start:     start,
+			End:       start.Add(sa.bucket),
+			MinHeight: b.Height,
+		}
+		sa.buckets[start] = bucket
+	}
+
+	bucket.Blocks++
+	if b.Height < bucket.MinHeight {
+		bucket.MinHeight = b.Height
+	}
+	if b.Height > bucket.MaxHeight {
+		bucket.MaxHeight = b.Height
+	}
+	if timed {
+		bucket.totalBlockTime += stats.BlockTime
+		bucket.timedBlocks++
+	}
+
+	bucket.Transactions += stats.Transactions
+	bucket.Fees = bucket.Fees.Add(stats.Fees)
+	bucket.ContractFormations += stats.ContractFormations
+	bucket.ContractRevisions += stats.ContractRevisions
+	bucket.StorageProofs += stats.StorageProofs
+	bucket.HostAnnouncements += stats.HostAnnouncements
+}
+
+// result returns the aggregated statistics with buckets sorted by time
+func (sa *statsAggregator) result() ChainStats {
+	sa.stats.Buckets = sa.stats.Buckets[:0]
+	for _, bucket := range sa.buckets {
+		if bucket.timedBlocks != 0 {
+			bucket.AverageBlockTime = bucket.totalBlockTime / time.Duration(bucket.timedBlocks)
+		}
+		sa.stats.Buckets = append(sa.stats.Buckets, *bucket)
+	}
+
+	sort.Slice(sa.stats.Buckets, func(i, j int) bool {
+		return sa.stats.Buckets[i].Start.Before(sa.stats.Buckets[j].Start)
+	})
+	return sa.stats
+}
+
+// AggregateChainStats calculates per-block statistics of the blocks and groups
+// them into buckets of the specified duration by block timestamp. Buckets are
+// not calculated if bucket is zero.
+func AggregateChainStats(blocks []Block, bucket time.Duration) ChainStats {
+	sorted := append([]Block(nil), blocks...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Height < sorted[j].Height
+	})
+
+	sa := &statsAggregator{
+		bucket:  bucket,
+		buckets: make(map[time.Time]*BucketStats),
+	}
+	for _, b := range sorted {
+		sa.add(b)
+	}
+
+	return sa.result()
+}
+
+// IterateBlocks calls fn with each block from start to end inclusive in height
+// order, requesting blocks from the Sia Central explorer in batches. Iteration
+// stops at the first error returned by fn.
+func (a *APIClient) IterateBlocks(start, end uint64, fn func(Block) error) error {
+	if start > end {
+		return errors.New("start height must not be greater than end height")
+	}
+
+	for batchStart := start; batchStart <= end; batchStart += blockBatchSize {
+		batchEnd := batchStart + blockBatchSize - 1
+		if batchEnd > end {
+			batchEnd = end
+		}
+
+		heights := make([]uint64, 0, batchEnd-batchStart+1)
+		for height := batchStart; height <= batchEnd; height++ {
+			heights = append(heights, height)
+		}
+
+		blocks, err := a.FindBlocksByHeight(heights...)
+		if err != nil {
+			return fmt.Errorf("unable to get blocks %d-%d: %w", batchStart, batchEnd, err)
+		} else if len(blocks) != len(heights) {
+			return fmt.Errorf("expected %d blocks from %d-%d, got %d", len(heights), batchStart, batchEnd, len(blocks))
+		}
+
+		sort.Slice(blocks, func(i, j int) bool {
+			return blocks[i].Height < blocks[j].Height
+		})
+
+		for _, b := range blocks {
+			if err := fn(b); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ChainStatsByHeight calculates the statistics of the blocks from start to end
+// inclusive. The block before start is also retrieved so the block time of the
+// first block can be calculated.
+func (a *APIClient) ChainStatsByHeight(start, end uint64, bucket time.Duration) (stats ChainStats, err error) {
+	sa := &statsAggregator{
+		bucket:  bucket,
+		buckets: make(map[time.Time]*BucketStats),
+	}
+
+	if start > 0 {
+		var prev Block
+
+		prev, err = a.GetBlockByHeight(start - 1)
+		if err != nil {
+			err = fmt.Errorf("unable to get block %d: %w", start-1, err)
+			return
+		}
+		sa.prev = &prev
+	}
+
+	err = a.IterateBlocks(start, end, func(b Block) error {
+		sa.add(b)
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	stats = sa.result()
+	return
+}
+
+// ChainStatsByTime calculates the statistics of the blocks with timestamps
+// between start and end
+func (a *APIClient) ChainStatsByTime(start, end time.Time, bucket time.Duration) (stats ChainStats, err error) {
+	if start.After(end) {
+		err = errors.New("start time must not be after end time")
+		return
+	}
+
+	ti := NewTimestampIndex(a)
+	startHeight, err := ti.HeightAt(start)
+	if err != nil {
+		err = fmt.Errorf("unable to find start height: %w", err)
+		return
+	}
+
+	// HeightAt returns the last block at or before the time, the range
+	// should start with the first block after it
+	startTimestamp, err := ti.TimestampAt(startHeight)
+	if err != nil {
+		err = fmt.Errorf("unable to get start timestamp: %w", err)
+		return
+	} else if startTimestamp.Before(start) {
+		startHeight++
+	}
+
+	endHeight, err := ti.HeightAt(end)
+	if err != nil {
+		err = fmt.Errorf("unable to find end height: %w", err)
+		return
+	} else if endHeight < startHeight {
+		return
+	}
+
+	return a.ChainStatsByHeight(startHeight, endHeight, bucket)
+}
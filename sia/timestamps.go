@@ -0,0 +1,270 @@
+package sia
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// timestampProbes is the maximum number of heights probed in each round of
+	// a timestamp search
+	timestampProbes = 16
+	// timestampWindow is the number of blocks around the estimated height
+	// probed in the first round of a timestamp search, roughly one day
+	timestampWindow = 144
+	// estimateSampleBlocks is the number of recent blocks used to calculate the
+	// average block time when estimating the time of future heights
+	estimateSampleBlocks = 144
+	// estimateZScore is the z-score of the estimate's confidence interval,
+	// roughly 95%
+	estimateZScore = 1.96
+)
+
+var (
+	blockTime = time.Duration(types.BlockFrequency) * time.Second
+)
+
+// TimeEstimate is the estimated time of a block height. Heights that have
+// already been mined have an exact time, future heights are projected from the
+// recent average block time with a confidence interval.
+type TimeEstimate struct {
+	Height    uint64    `json:"height"`
+	Time      time.Time `json:"time"`
+	Earliest  time.Time `json:"earliest"`
+	Latest    time.Time `json:"latest"`
+	Estimated bool      `json:"estimated"`
+}
+
+// TimestampIndex finds blocks by timestamp using the Sia Central explorer.
+// The timestamps of probed heights are cached so repeated lookups require
+// fewer requests.
+type TimestampIndex struct {
+	client *APIClient
+
+	mu         sync.Mutex
+	timestamps map[uint64]time.Time
+}
+
+// NewTimestampIndex creates a new timestamp index using the client
+func NewTimestampIndex(client *APIClient) *TimestampIndex {
+	return &TimestampIndex{
+		client:     client,
+		timestamps: make(map[uint64]time.Time),
+	}
+}
+
+// blockTimestamps returns the timestamps of the blocks at the heights, only
+// fetching heights that are not already cached
+func (ti *TimestampIndex) blockTimestamps(heights ...uint64) (map[uint64]time.Time, error) {
+	ti.mu.Lock()
+	timestamps := make(map[uint64]time.Time, len(heights))
+	var missing []uint64
+	for _, height := range heights {
+		if ts, exists := ti.timestamps[height]; exists {
+			timestamps[height] = ts
+		} else {
+			missing = append(missing, height)
+		}
+	}
+	ti.mu.Unlock()
+
+	err := batches(len(missing), blockBatchSize, func(start, end int) error {
+		blocks, err := ti.client.FindBlocksByHeight(missing[start:end]...)
+		if err != nil {
+			return err
+		}
+
+		ti.mu.Lock()
+		for _, block := range blocks {
+			ti.timestamps[block.Height] = block.Timestamp
+			timestamps[block.Height] = block.Timestamp
+		}
+		ti.mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, height := range heights {
+		if _, exists := timestamps[height]; !exists {
+			return nil, errors.New("block not found")
+		}
+	}
+
+	return timestamps, nil
+}
+
+// TimestampAt returns the timestamp of the block at the height
+func (ti *TimestampIndex) TimestampAt(height uint64) (time.Time, error) {
+	timestamps, err := ti.blockTimestamps(height)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return timestamps[height], nil
+}
+
+// HeightAt returns the height of the block at or just before the timestamp.
+// Block timestamps are not strictly increasing, so the returned block is the
+// last block with a timestamp at or before t whose successor has a timestamp
+// after t.
+func (ti *TimestampIndex) HeightAt(t time.Time) (uint64, error) {
+	tip, err := ti.client.GetLatestBlock()
+	if err != nil {
+		return 0, err
+	}
+
+	ti.mu.Lock()
+	ti.timestamps[tip.Height] = tip.Timestamp
+	ti.mu.Unlock()
+
+	if !t.Before(tip.Timestamp) {
+		return tip.Height, nil
+	}
+
+	genesis, err := ti.TimestampAt(0)
+	if err != nil {
+		return 0, err
+	} else if t.Before(genesis) {
+		return 0, errors.New("timestamp is before the genesis block")
+	}
+
+	// invariant: timestamp(lo) <= t < timestamp(hi)
+	lo, hi := uint64(0), tip.Height
+
+	// narrow the first round of probes around the height estimated from the
+	// target block time
+	var probes []uint64
+	behind := uint64(tip.Timestamp.Sub(t) / blockTime)
+	if behind < tip.Height {
+		est := tip.Height - behind
+		for _, height := range []uint64{est - timestampWindow, est, est + timestampWindow} {
+			if height > lo && height < hi {
+				probes = append(probes, height)
+			}
+		}
+	}
+
+	for hi-lo > 1 {
+		if len(probes) == 0 {
+			step := (hi - lo) / (timestampProbes + 1)
+			if step == 0 {
+				step = 1
+			}
+
+			for height := lo + step; height < hi && len(probes) < timestampProbes; height += step {
+				probes = append(probes, height)
+			}
+		}
+
+		timestamps, err := ti.blockTimestamps(probes...)
+		if err != nil {
+			return 0, err
+		}
+
+		sort.Slice(probes, func(i, j int) bool { return probes[i] < probes[j] })
+		for _, height := range probes {
+			if timestamps[height].After(t) {
+				hi = height
+				break
+			}
+
+			lo = height
+		}
+
+		probes = probes[:0]
+	}
+
+	return lo, nil
+}
+
+// EstimateTime estimates the time of the block at the height. If the height has
+// already been mined the block's timestamp is returned, otherwise the time is
+// projected from the average block time of recent blocks.
+func (ti *TimestampIndex) EstimateTime(height uint64) (estimate TimeEstimate, err error) {
+	index, err := ti.client.GetChainIndex()
+	if err != nil {
+		return
+	}
+
+	estimate.Height = height
+	if height <= index.Height {
+		estimate.Time, err = ti.TimestampAt(height)
+		estimate.Earliest, estimate.Latest = estimate.Time, estimate.Time
+		return
+	}
+
+	start := uint64(0)
+	if index.Height > estimateSampleBlocks {
+		start = index.Height - estimateSampleBlocks
+	}
+
+	heights := make([]uint64, 0, index.Height-start+1)
+	for h := start; h <= index.Height; h++ {
+		heights = append(heights, h)
+	}
+
+	timestamps, err := ti.blockTimestamps(heights...)
+	if err != nil {
+		return
+	}
+
+	tip := timestamps[index.Height]
+	mean, stddev := blockTime, time.Duration(0)
+	if n := len(heights) - 1; n > 0 {
+		mean = tip.Sub(timestamps[start]) / time.Duration(n)
+
+		var variance float64
+		for i := 1; i < len(heights); i++ {
+			d := float64(timestamps[heights[i]].Sub(timestamps[heights[i-1]]) - mean)
+			variance += d * d
+		}
+		stddev = time.Duration(math.Sqrt(variance / float64(n)))
+	}
+
+	// the time to mine n blocks is the sum of n block intervals, so its
+	// deviation grows with the square root of n
+	blocks := height - index.Height
+	margin := time.Duration(estimateZScore * float64(stddev) * math.Sqrt(float64(blocks)))
+
+	estimate.Time = tip.Add(mean * time.Duration(blocks))
+	estimate.Earliest = estimate.Time.Add(-margin)
+	estimate.Latest = estimate.Time.Add(margin)
+	estimate.Estimated = true
+	return
+}
+
+// EstimateHeight estimates the height of the block that will be mined at the
+// timestamp. Timestamps in the past are resolved using HeightAt.
+func (ti *TimestampIndex) EstimateHeight(t time.Time) (uint64, error) {
+	index, err := ti.client.GetChainIndex()
+	if err != nil {
+		return 0, err
+	}
+
+	tip, err := ti.TimestampAt(index.Height)
+	if err != nil {
+		return 0, err
+	} else if !t.After(tip) {
+		return ti.HeightAt(t)
+	}
+
+	estimate, err := ti.EstimateTime(index.Height + 1)
+	if err != nil {
+		return 0, err
+	}
+
+	// the estimate of the next block includes one average block interval
+	mean := estimate.Time.Sub(tip)
+	if mean <= 0 {
+		mean = blockTime
+	}
+
+	return index.Height + uint64(t.Sub(tip)/mean), nil
+}
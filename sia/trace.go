@@ -0,0 +1,314 @@
+package sia
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// TraceForward follows outputs to the transactions that spent them
+	TraceForward TraceDirection = "forward"
+	// TraceBackward follows inputs to the transactions that created them
+	TraceBackward TraceDirection = "backward"
+	// TraceBoth follows both inputs and outputs
+	TraceBoth TraceDirection = "both"
+)
+
+type (
+	// TraceDirection the direction funds are traced in
+	TraceDirection string
+
+	// FlowNode a transaction in a fund flow graph. Depth is the number of
+	// transactions between the node and the nearest starting transaction.
+	FlowNode struct {
+		ID        TransactionID `json:"id"`
+		Height    uint64        `json:"height"`
+		Timestamp time.Time     `json:"timestamp"`
+		Depth     int           `json:"depth"`
+	}
+
+	// FlowEdge a siacoin output linking the transaction that created it to the
+	// transaction that spent it. From is zero if the output was not created by
+	// a transaction, To is zero if the output is unspent.
+	FlowEdge struct {
+		OutputID   OutputID       `json:"output_id"`
+		From       TransactionID  `json:"from"`
+		To         TransactionID  `json:"to"`
+		UnlockHash UnlockHash     `json:"unlock_hash"`
+		Source     OutputSource   `json:"source"`
+		Value      types.Currency `json:"value"`
+	}
+
+	// FlowGraph a graph of siacoin transfers between transactions
+	FlowGraph struct {
+		Nodes []FlowNode `json:"nodes"`
+		Edges []FlowEdge `json:"edges"`
+	}
+
+	// fundTracer holds the state of a single trace
+	fundTracer struct {
+		client    *APIClient
+		direction TraceDirection
+		nodes     map[TransactionID]*FlowNode
+		edges     map[OutputID]*FlowEdge
+		// creators maps outputs to the transaction that created them for
+		// blocks that have already been retrieved
+		creators map[OutputID]TransactionID
+		heights  map[uint64]bool
+	}
+)
+
+// findTransactions retrieves the transactions in batches
+func (a *APIClient) findTransactions(ids []TransactionID) (transactions []Transaction, err error) {
+	err = batches(len(ids), maxBatchSize, func(start, end int) error {
+		batch, err := a.FindTransactionsByID(ids[start:end]...)
+		transactions = append(transactions, batch...)
+		return err
+	})
+	return
+}
+
+// findBlocks retrieves the blocks at the heights in small batches since
+// blocks include their transactions
+func (a *APIClient) findBlocks(heights []uint64) (blocks []Block, err error) {
+	err = batches(len(heights), blockBatchSize, func(start, end int) error {
+		batch, err := a.FindBlocksByHeight(heights[start:end]...)
+		blocks = append(blocks, batch...)
+		return err
+	})
+	return
+}
+
+// edge returns the edge for the output, adding it if it does not exist
+func (ft *fundTracer) edge(o SiacoinOutput) *FlowEdge {
+	e, exists := ft.edges[o.OutputID]
+	if !exists {
+		e = &FlowEdge{
+			OutputID:   o.OutputID,
+			UnlockHash: o.UnlockHash,
+			Source:     o.Source,
+			Value:      o.Value,
+		}
+		ft.edges[o.OutputID] = e
+	}
+
+	return e
+}
+
+// loadCreators retrieves the blocks the inputs' parent outputs were created in
+// and indexes the transactions that created their outputs
+func (ft *fundTracer) loadCreators(inputs []SiacoinInput) error {
+	var heights []uint64
+	for _, sci := range inputs {
+		if sci.Source != "" && !sci.Source.IsTransactionOutput() {
+			continue
+		} else if ft.heights[sci.BlockHeight] {
+			continue
+		}
+
+		ft.heights[sci.BlockHeight] = true
+		heights = append(heights, sci.BlockHeight)
+	}
+
+	if len(heights) == 0 {
+		return nil
+	}
+
+	blocks, err := ft.client.findBlocks(heights)
+	if err != nil {
+		return fmt.Errorf("unable to get blocks: %w", err)
+	}
+
+	for _, b := range blocks {
+		for _, txn := range b.Transactions {
+			for _, sco := range txn.SiacoinOutputs {
+				ft.creators[sco.OutputID] = txn.ID
+			}
+		}
+	}
+
+	return nil
+}
+
+// visit adds the transactions to the graph and returns the ids of the
+// transactions linked to them that have not been visited yet
+func (ft *fundTracer) visit(transactions []Transaction, depth int) (next []TransactionID, err error) {
+	queued := make(map[TransactionID]bool)
+	queue := func(id TransactionID) {
+		if id.IsZero() || queued[id] {
+			return
+		} else if _, exists := ft.nodes[id]; exists {
+			return
+		}
+
+		queued[id] = true
+		next = append(next, id)
+	}
+
+	var inputs []SiacoinInput
+	for _, txn := range transactions {
+		ft.nodes[txn.ID] = &FlowNode{
+			ID:        txn.ID,
+			Height:    txn.BlockHeight,
+			Timestamp: txn.Timestamp,
+			Depth:     depth,
+		}
+
+		if ft.direction != TraceBackward {
+			for _, sco := range txn.SiacoinOutputs {
+				e := ft.edge(sco)
+				e.From = txn.ID
+				e.To = sco.SpentTransactionID
+				queue(sco.SpentTransactionID)
+			}
+		}
+
+		if ft.direction != TraceForward {
+			inputs = append(inputs, txn.SiacoinInputs...)
+		}
+	}
+
+	if len(inputs) == 0 {
+		return
+	}
+
+	if err = ft.loadCreators(inputs); err != nil {
+		return
+	}
+
+	for _, txn := range transactions {
+		for _, sci := range txn.SiacoinInputs {
+			e := ft.edge(sci.SiacoinOutput)
+			e.To = txn.ID
+			if creator, exists := ft.creators[sci.OutputID]; exists {
+				e.From = creator
+				queue(creator)
+			}
+		}
+	}
+
+	return
+}
+
+// graph returns the traced graph sorted by depth and height
+func (ft *fundTracer) graph() (g FlowGraph) {
+	for _, n := range ft.nodes {
+		g.Nodes = append(g.Nodes, *n)
+	}
+
+	for _, e := range ft.edges {
+		g.Edges = append(g.Edges, *e)
+	}
+
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		if g.Nodes[i].Depth != g.Nodes[j].Depth {
+			return g.Nodes[i].Depth < g.Nodes[j].Depth
+		} else if g.Nodes[i].Height != g.Nodes[j].Height {
+			return g.Nodes[i].Height < g.Nodes[j].Height
+		}
+		return g.Nodes[i].ID.String() < g.Nodes[j].ID.String()
+	})
+
+	sort.Slice(g.Edges, func(i, j int) bool {
+		return g.Edges[i].OutputID.String() < g.Edges[j].OutputID.String()
+	})
+
+	return
+}
+
+// TraceFunds follows the flow of siacoins from the transactions up to depth
+// transactions away using the Sia Central explorer. Forward traces follow
+// outputs to the transactions that spent them, backward traces follow inputs
+// to the transactions that created them. Outputs created by miner payouts,
+// contracts or siafund claims end a backward trace.
+func (a *APIClient) TraceFunds(direction TraceDirection, depth int, ids ...TransactionID) (g FlowGraph, err error) {
+	switch direction {
+	case TraceForward, TraceBackward, TraceBoth:
+	default:
+		err = fmt.Errorf("unknown trace direction %q", direction)
+		return
+	}
+
+	if len(ids) == 0 {
+		err = errors.New("no transactions to trace")
+		return
+	} else if depth < 0 {
+		err = errors.New("depth must not be negative")
+		return
+	}
+
+	ft := &fundTracer{
+		client:    a,
+		direction: direction,
+		nodes:     make(map[TransactionID]*FlowNode),
+		edges:     make(map[OutputID]*FlowEdge),
+		creators:  make(map[OutputID]TransactionID),
+		heights:   make(map[uint64]bool),
+	}
+
+	frontier := ids
+	for i := 0; i <= depth && len(frontier) != 0; i++ {
+		var transactions []Transaction
+
+		transactions, err = a.findTransactions(frontier)
+		if err != nil {
+			err = fmt.Errorf("unable to get transactions: %w", err)
+			return
+		}
+
+		frontier, err = ft.visit(transactions, i)
+		if err != nil {
+			return
+		}
+	}
+
+	g = ft.graph()
+	return
+}
+
+// shortID truncates an id for display
+func shortID(id string) string {
+	if len(id) > 16 {
+		return id[:16]
+	}
+	return id
+}
+
+// WriteDOT writes the graph in the Graphviz DOT format. Outputs that were not
+// created by a transaction or are unspent are drawn as separate nodes.
+func (g FlowGraph) WriteDOT(w io.Writer) (err error) {
+	printf := func(format string, args ...interface{}) {
+		if err != nil {
+			return
+		}
+		_, err = fmt.Fprintf(w, format, args...)
+	}
+
+	printf("digraph funds {\n\trankdir=LR;\n")
+	for _, n := range g.Nodes {
+		printf("\t%q [shape=box, label=%q];\n", n.ID.String(), fmt.Sprintf("%s\nheight %d", shortID(n.ID.String()), n.Height))
+	}
+
+	for _, e := range g.Edges {
+		from, to := e.From.String(), e.To.String()
+		if e.From.IsZero() {
+			from = "source:" + e.OutputID.String()
+			printf("\t%q [shape=ellipse, label=%q];\n", from, string(e.Source))
+		}
+
+		if e.To.IsZero() {
+			to = "unspent:" + e.OutputID.String()
+			printf("\t%q [shape=ellipse, label=%q];\n", to, "unspent\n"+shortID(e.UnlockHash.String()))
+		}
+
+		printf("\t%q -> %q [label=%q];\n", from, to, e.Value.HumanString())
+	}
+	printf("}\n")
+
+	return
+}
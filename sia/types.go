@@ -10,12 +10,12 @@ import (
 type (
 	//Announcement a host announcement on the blockchain
 	Announcement struct {
-		TransactionID string    `json:"transaction_id"`
-		BlockID       string    `json:"block_id"`
-		PublicKey     string    `json:"public_key"`
-		NetAddress    string    `json:"net_address"`
-		Height        uint64    `json:"block_height"`
-		Timestamp     time.Time `json:"timestamp,omitempty"`
+		TransactionID TransactionID `json:"transaction_id"`
+		BlockID       BlockID       `json:"block_id"`
+		PublicKey     PublicKey     `json:"public_key"`
+		NetAddress    string        `json:"net_address"`
+		Height        uint64        `json:"block_height"`
+		Timestamp     time.Time     `json:"timestamp,omitempty"`
 	}
 
 	// AvgHostBenchmark AvgHostBenchmark
@@ -40,7 +40,7 @@ type (
 	//HostDetails the latest details on the host
 	HostDetails struct {
 		NetAddress         string                 `json:"net_address"`
-		PublicKey          string                 `json:"public_key"`
+		PublicKey          PublicKey              `json:"public_key"`
 		Version            string                 `json:"version"`
 		EstimatedUptime    float32                `json:"estimated_uptime"`
 		Online             bool                   `json:"online"`
@@ -105,10 +105,10 @@ type (
 
 	//SiacoinOutput an output of siacoins for a transaction
 	SiacoinOutput struct {
-		OutputID           string         `json:"output_id"`
-		UnlockHash         string         `json:"unlock_hash"`
-		Source             string         `json:"source"`
-		SpentTransactionID string         `json:"spent_transaction_id"`
+		OutputID           OutputID       `json:"output_id"`
+		UnlockHash         UnlockHash     `json:"unlock_hash"`
+		Source             OutputSource   `json:"source"`
+		SpentTransactionID TransactionID  `json:"spent_transaction_id"`
 		MaturityHeight     uint64         `json:"maturity_height"`
 		BlockHeight        uint64         `json:"block_height"`
 		Value              types.Currency `json:"value"`
@@ -116,10 +116,10 @@ type (
 
 	//SiafundOutput an output of siafunds for a transaction
 	SiafundOutput struct {
-		OutputID           string         `json:"output_id"`
-		BlockID            string         `json:"block_id"`
-		SpentTransactionID string         `json:"spent_transaction_id"`
-		UnlockHash         string         `json:"unlock_hash"`
+		OutputID           OutputID       `json:"output_id"`
+		BlockID            BlockID        `json:"block_id"`
+		SpentTransactionID TransactionID  `json:"spent_transaction_id"`
+		UnlockHash         UnlockHash     `json:"unlock_hash"`
 		BlockHeight        uint64         `json:"block_height"`
 		Value              types.Currency `json:"value"`
 		ClaimStart         types.Currency `json:"claim_start"`
@@ -129,18 +129,18 @@ type (
 	//SiafundInput an input of siafunds for a transaction
 	SiafundInput struct {
 		SiafundOutput
-		ClaimUnlockHash  string          `json:"claim_unlock_hash"`
+		ClaimUnlockHash  UnlockHash      `json:"claim_unlock_hash"`
 		UnlockConditions UnlockCondition `json:"unlock_conditions"`
 	}
 
 	//StorageContract a storage contract on the blockchain
 	StorageContract struct {
-		ID                     string            `json:"id"`
-		BlockID                string            `json:"block_id"`
-		TransactionID          string            `json:"transaction_id"`
+		ID                     ContractID        `json:"id"`
+		BlockID                BlockID           `json:"block_id"`
+		TransactionID          TransactionID     `json:"transaction_id"`
 		MerkleRoot             string            `json:"merkle_root"`
-		UnlockHash             string            `json:"unlock_hash"`
-		Status                 string            `json:"status"`
+		UnlockHash             UnlockHash        `json:"unlock_hash"`
+		Status                 ContractStatus    `json:"status"`
 		RevisionNumber         uint64            `json:"revision_number"`
 		NegotiationHeight      uint64            `json:"negotiation_height"`
 		ExpirationHeight       uint64            `json:"expiration_height"`
@@ -161,19 +161,19 @@ type (
 
 	//StorageProof a storage proof on the blockchain
 	StorageProof struct {
-		ContractID    string    `json:"contract_id"`
-		TransactionID string    `json:"transaction_id"`
-		BlockID       string    `json:"block_id"`
-		BlockHeight   uint64    `json:"block_height"`
-		Segment       [64]byte  `json:"segment"`
-		Hashset       []string  `json:"hashset"`
-		Timestamp     time.Time `json:"timestamp"`
+		ContractID    ContractID    `json:"contract_id"`
+		TransactionID TransactionID `json:"transaction_id"`
+		BlockID       BlockID       `json:"block_id"`
+		BlockHeight   uint64        `json:"block_height"`
+		Segment       [64]byte      `json:"segment"`
+		Hashset       []string      `json:"hashset"`
+		Timestamp     time.Time     `json:"timestamp"`
 	}
 
 	//Transaction a transaction on the blockchain
 	Transaction struct {
-		ID                    string                 `json:"id"`
-		BlockID               string                 `json:"block_id"`
+		ID                    TransactionID          `json:"id"`
+		BlockID               BlockID                `json:"block_id"`
 		BlockHeight           uint64                 `json:"block_height,omitempty"`
 		Confirmations         uint64                 `json:"confirmations"`
 		BlockIndex            int                    `json:"-"`
@@ -194,9 +194,9 @@ type (
 
 	//UnlockCondition unlock conditions of a transaction input
 	UnlockCondition struct {
-		PublicKeys         []string `json:"public_keys"`
-		Timelock           uint64   `json:"timelock"`
-		RequiredSignatures uint64   `json:"required_signatures"`
+		PublicKeys         []PublicKey `json:"public_keys"`
+		Timelock           uint64      `json:"timelock"`
+		RequiredSignatures uint64      `json:"required_signatures"`
 	}
 
 	//CoveredFields the covered fields of a transaction signature and their indexes
@@ -214,18 +214,18 @@ type (
 
 	//TransactionSignature a signature verifying a part of the transaction
 	TransactionSignature struct {
-		ParentID       string        `json:"parent_id"`
-		TransactionID  string        `json:"transaction_id"`
-		BlockID        string        `json:"block_id"`
-		Signature      string        `json:"signature"`
-		PublicKeyIndex uint64        `json:"public_key_index"`
-		CoveredFields  CoveredFields `json:"covered_fields"`
+		ParentID       SignatureParentID `json:"parent_id"`
+		TransactionID  TransactionID     `json:"transaction_id"`
+		BlockID        BlockID           `json:"block_id"`
+		Signature      string            `json:"signature"`
+		PublicKeyIndex uint64            `json:"public_key_index"`
+		CoveredFields  CoveredFields     `json:"covered_fields"`
 	}
 
 	//Block a block on the Sia blockchain
 	Block struct {
-		ID                string          `json:"id"`
-		ParentID          string          `json:"parent_id"`
+		ID                BlockID         `json:"id"`
+		ParentID          BlockID         `json:"parent_id"`
 		Height            uint64          `json:"height"`
 		Nonce             [8]byte         `json:"nonce"`
 		Transactions      []Transaction   `json:"transactions"`
@@ -237,7 +237,7 @@ type (
 	//ConnectionReport information about the connection
 	ConnectionReport struct {
 		NetAddress    string               `json:"netaddress"`
-		PublicKey     string               `json:"public_key"`
+		PublicKey     PublicKey            `json:"public_key"`
 		ConnectedIP   string               `json:"connected_ip"`
 		Resolved      bool                 `json:"resolved"`
 		Announced     bool                 `json:"announced"`
@@ -261,13 +261,13 @@ type (
 
 	//AddressUsage AddressUsage
 	AddressUsage struct {
-		Address   string `json:"address"`
-		UsageType string `json:"usage_type"`
+		Address   UnlockHash       `json:"address"`
+		UsageType AddressUsageType `json:"usage_type"`
 	}
 
 	ChainIndex struct {
-		ID       string `json:"id"`
-		ParentID string `json:"parent_id"`
-		Height   uint64 `json:"height"`
+		ID       BlockID `json:"id"`
+		ParentID BlockID `json:"parent_id"`
+		Height   uint64  `json:"height"`
 	}
 )
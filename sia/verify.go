@@ -0,0 +1,243 @@
+package sia
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/types"
+)
+
+// VerifyTransaction reconstructs the transaction locally and checks that its
+// id matches the id returned by the API. ErrRevisionUnverifiable is returned
+// for transactions containing contract revisions.
+func VerifyTransaction(t Transaction) error {
+	txn, err := t.SiadTransaction()
+	if err != nil {
+		return err
+	}
+
+	if id := TransactionID(txn.ID()); id != t.ID {
+		return fmt.Errorf("transaction id mismatch: expected %s, computed %s", t.ID, id)
+	}
+
+	return nil
+}
+
+// VerifyBlock reconstructs the block locally and checks that its transactions
+// and the id computed from the header and merkle root match the ids returned by
+// the API. Blocks containing contract revisions cannot be checked, for those
+// an error matching ErrRevisionUnverifiable is returned rather than a
+// verification failure.
+func VerifyBlock(b Block) error {
+	for _, t := range b.Transactions {
+		if err := VerifyTransaction(t); err != nil {
+			return fmt.Errorf("block %d: %w", b.Height, err)
+		}
+	}
+
+	block, err := b.SiadBlock()
+	if err != nil {
+		return fmt.Errorf("block %d: %w", b.Height, err)
+	}
+
+	if id := BlockID(block.ID()); id != b.ID {
+		return fmt.Errorf("block %d id mismatch: expected %s, computed %s (merkle root %s)", b.Height, b.ID, id, block.MerkleRoot())
+	}
+
+	return nil
+}
+
+// VerifyBlockChain checks that the blocks form a contiguous chain where each
+// block's parent id matches the id of the block before it. The blocks are
+// sorted by height before they are checked. The ids themselves are not
+// recomputed, use VerifyBlock to check each block individually.
+func VerifyBlockChain(blocks []Block) error {
+	if len(blocks) == 0 {
+		return errors.New("no blocks to verify")
+	}
+
+	sorted := make([]Block, len(blocks))
+	copy(sorted, blocks)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Height < sorted[j].Height
+	})
+
+	for i := 1; i < len(sorted); i++ {
+		prev, current := sorted[i-1], sorted[i]
+
+		if current.Height != prev.Height+1 {
+			return fmt.Errorf("missing blocks between height %d and %d", prev.Height, current.Height)
+		}
+
+		if current.ParentID != prev.ID {
+			return fmt.Errorf("block %d parent id %s does not match block %d id %s", current.Height, current.ParentID, prev.Height, prev.ID)
+		}
+	}
+
+	return nil
+}
+
+// verifyUnlockConditions checks that the unlock conditions of an input hash
+// to the unlock hash of the output being spent
+func verifyUnlockConditions(outputID OutputID, unlockHash UnlockHash, uc types.UnlockConditions) error {
+	if unlockHash.IsZero() {
+		return fmt.Errorf("input %s is missing the unlock hash of the spent output", outputID)
+	}
+
+	if actual := UnlockHash(uc.UnlockHash()); actual != unlockHash {
+		return fmt.Errorf("input %s unlock conditions hash to %s, expected %s", outputID, actual, unlockHash)
+	}
+
+	return nil
+}
+
+// verifyCoveredIndices checks that the covered indices of a field are sorted,
+// unique and within the number of elements of the field
+func verifyCoveredIndices(field string, indices []uint64, n int) error {
+	for i, index := range indices {
+		if index >= uint64(n) {
+			return fmt.Errorf("covered %s index %d out of range, transaction has %d", field, index, n)
+		} else if i > 0 && index <= indices[i-1] {
+			return fmt.Errorf("covered %s indices are not sorted and unique", field)
+		}
+	}
+
+	return nil
+}
+
+// verifyCoveredFields checks the covered fields of a signature the same way
+// consensus does, so SigHash is never called with invalid fields
+func verifyCoveredFields(txn types.Transaction, cf types.CoveredFields) error {
+	if cf.WholeTransaction && (len(cf.SiacoinInputs) != 0 || len(cf.SiacoinOutputs) != 0 ||
+		len(cf.FileContracts) != 0 || len(cf.FileContractRevisions) != 0 || len(cf.StorageProofs) != 0 ||
+		len(cf.SiafundInputs) != 0 || len(cf.SiafundOutputs) != 0 || len(cf.MinerFees) != 0 ||
+		len(cf.ArbitraryData) != 0) {
+		return errors.New("whole transaction flag is set with other covered fields")
+	}
+
+	fields := []struct {
+		name    string
+		indices []uint64
+		n       int
+	}{
+		{"siacoin input", cf.SiacoinInputs, len(txn.SiacoinInputs)},
+		{"siacoin output", cf.SiacoinOutputs, len(txn.SiacoinOutputs)},
+		{"file contract", cf.FileContracts, len(txn.FileContracts)},
+		{"file contract revision", cf.FileContractRevisions, len(txn.FileContractRevisions)},
+		{"storage proof", cf.StorageProofs, len(txn.StorageProofs)},
+		{"siafund input", cf.SiafundInputs, len(txn.SiafundInputs)},
+		{"siafund output", cf.SiafundOutputs, len(txn.SiafundOutputs)},
+		{"miner fee", cf.MinerFees, len(txn.MinerFees)},
+		{"arbitrary data", cf.ArbitraryData, len(txn.ArbitraryData)},
+		{"transaction signature", cf.TransactionSignatures, len(txn.TransactionSignatures)},
+	}
+
+	for _, f := range fields {
+		if err := verifyCoveredIndices(f.name, f.indices, f.n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// VerifyTransactionSignatures checks that the unlock conditions of each input
+// hash to the unlock hash of the spent output and that every signature is valid
+// for its public key and covered fields. The height is used to determine the
+// replay protection prefix and should be the height the transaction was
+// confirmed at, or the current height for unconfirmed transactions. Inputs
+// timelocked past the height are rejected. The API does not return signature
+// timelocks, so they are not checked.
+func VerifyTransactionSignatures(t Transaction, height uint64) error {
+	txn, err := t.SiadTransaction()
+	if err != nil {
+		return err
+	}
+
+	type pendingInput struct {
+		uc        types.UnlockConditions
+		remaining uint64
+		used      map[uint64]bool
+	}
+
+	inputs := make(map[crypto.Hash]*pendingInput)
+	for i, input := range txn.SiacoinInputs {
+		if err := verifyUnlockConditions(t.SiacoinInputs[i].OutputID, t.SiacoinInputs[i].UnlockHash, input.UnlockConditions); err != nil {
+			return err
+		} else if uint64(input.UnlockConditions.Timelock) > height {
+			return fmt.Errorf("input %s is timelocked until height %d", t.SiacoinInputs[i].OutputID, input.UnlockConditions.Timelock)
+		}
+
+		inputs[crypto.Hash(input.ParentID)] = &pendingInput{
+			uc:        input.UnlockConditions,
+			remaining: input.UnlockConditions.SignaturesRequired,
+			used:      make(map[uint64]bool),
+		}
+	}
+
+	for i, input := range txn.SiafundInputs {
+		if err := verifyUnlockConditions(t.SiafundInputs[i].OutputID, t.SiafundInputs[i].UnlockHash, input.UnlockConditions); err != nil {
+			return err
+		} else if uint64(input.UnlockConditions.Timelock) > height {
+			return fmt.Errorf("input %s is timelocked until height %d", t.SiafundInputs[i].OutputID, input.UnlockConditions.Timelock)
+		}
+
+		inputs[crypto.Hash(input.ParentID)] = &pendingInput{
+			uc:        input.UnlockConditions,
+			remaining: input.UnlockConditions.SignaturesRequired,
+			used:      make(map[uint64]bool),
+		}
+	}
+
+	for i, sig := range txn.TransactionSignatures {
+		input, exists := inputs[sig.ParentID]
+		if !exists {
+			return fmt.Errorf("signature %d references unknown parent %s", i, sig.ParentID)
+		} else if input.remaining == 0 {
+			return fmt.Errorf("signature %d is frivolous, parent %s already has its required signatures", i, sig.ParentID)
+		} else if input.used[sig.PublicKeyIndex] {
+			return fmt.Errorf("signature %d reuses public key %d", i, sig.PublicKeyIndex)
+		} else if sig.PublicKeyIndex >= uint64(len(input.uc.PublicKeys)) {
+			return fmt.Errorf("signature %d references nonexistent public key %d", i, sig.PublicKeyIndex)
+		} else if err := verifyCoveredFields(txn, sig.CoveredFields); err != nil {
+			return fmt.Errorf("signature %d has invalid covered fields: %w", i, err)
+		}
+
+		pk := input.uc.PublicKeys[sig.PublicKeyIndex]
+		switch pk.Algorithm {
+		case types.SignatureEd25519:
+			var cpk crypto.PublicKey
+			var csig crypto.Signature
+
+			if len(pk.Key) != len(cpk) {
+				return fmt.Errorf("signature %d public key has invalid length %d", i, len(pk.Key))
+			} else if len(sig.Signature) != len(csig) {
+				return fmt.Errorf("signature %d has invalid length %d", i, len(sig.Signature))
+			}
+
+			copy(cpk[:], pk.Key)
+			copy(csig[:], sig.Signature)
+
+			if err := crypto.VerifyHash(txn.SigHash(i, types.BlockHeight(height)), cpk, csig); err != nil {
+				return fmt.Errorf("signature %d for parent %s is invalid: %w", i, sig.ParentID, err)
+			}
+		case types.SignatureEntropy:
+			return fmt.Errorf("signature %d signs an entropy public key", i)
+		default:
+			// unrecognized algorithms are treated as valid by consensus
+		}
+
+		input.used[sig.PublicKeyIndex] = true
+		input.remaining--
+	}
+
+	for id, input := range inputs {
+		if input.remaining > 0 {
+			return fmt.Errorf("input %s is missing %d signatures", id, input.remaining)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package sia
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.sia.tech/siad/crypto"
+	"go.sia.tech/siad/types"
+)
+
+// testAPITransaction converts a siad transaction spending siacoins into the
+// API representation
+func testAPITransaction(txn types.Transaction) (t Transaction) {
+	t.ID = TransactionID(txn.ID())
+	for _, sci := range txn.SiacoinInputs {
+		uc := UnlockCondition{
+			Timelock:           uint64(sci.UnlockConditions.Timelock),
+			RequiredSignatures: sci.UnlockConditions.SignaturesRequired,
+		}
+		for _, pk := range sci.UnlockConditions.PublicKeys {
+			uc.PublicKeys = append(uc.PublicKeys, PublicKey(pk))
+		}
+
+		t.SiacoinInputs = append(t.SiacoinInputs, SiacoinInput{
+			SiacoinOutput: SiacoinOutput{
+				OutputID:   OutputID(sci.ParentID),
+				UnlockHash: UnlockHash(sci.UnlockConditions.UnlockHash()),
+			},
+			UnlockConditions: uc,
+		})
+	}
+
+	for i, sco := range txn.SiacoinOutputs {
+		t.SiacoinOutputs = append(t.SiacoinOutputs, SiacoinOutput{
+			OutputID:   OutputID(txn.SiacoinOutputID(uint64(i))),
+			UnlockHash: UnlockHash(sco.UnlockHash),
+			Value:      sco.Value,
+		})
+	}
+
+	t.MinerFees = txn.MinerFees
+	for _, sig := range txn.TransactionSignatures {
+		t.TransactionSignatures = append(t.TransactionSignatures, TransactionSignature{
+			ParentID:       SignatureParentID(sig.ParentID),
+			Signature:      hex.EncodeToString(sig.Signature),
+			PublicKeyIndex: sig.PublicKeyIndex,
+			CoveredFields: CoveredFields{
+				WholeTransaction: sig.CoveredFields.WholeTransaction,
+				SiacoinInputs:    sig.CoveredFields.SiacoinInputs,
+				SiacoinOutputs:   sig.CoveredFields.SiacoinOutputs,
+				MinerFees:        sig.CoveredFields.MinerFees,
+			},
+		})
+	}
+	return
+}
+
+// testSignedTransaction returns a transaction spending a single input signed
+// with full covered fields at the height
+func testSignedTransaction(timelock, height uint64) types.Transaction {
+	sk, pk := crypto.GenerateKeyPair()
+	uc := types.UnlockConditions{
+		PublicKeys:         []types.SiaPublicKey{types.Ed25519PublicKey(pk)},
+		SignaturesRequired: 1,
+		Timelock:           types.BlockHeight(timelock),
+	}
+
+	txn := types.Transaction{
+		SiacoinInputs: []types.SiacoinInput{{
+			ParentID:         types.SiacoinOutputID{1},
+			UnlockConditions: uc,
+		}},
+		SiacoinOutputs: []types.SiacoinOutput{{
+			Value:      types.SiacoinPrecision,
+			UnlockHash: types.UnlockHash{2},
+		}},
+		MinerFees: []types.Currency{types.NewCurrency64(1)},
+		TransactionSignatures: []types.TransactionSignature{{
+			ParentID:      crypto.Hash{1},
+			CoveredFields: types.FullCoveredFields,
+		}},
+	}
+
+	sig := crypto.SignHash(txn.SigHash(0, types.BlockHeight(height)), sk)
+	txn.TransactionSignatures[0].Signature = sig[:]
+	return txn
+}
+
+func TestVerifyTransactionSignatures(t *testing.T) {
+	const height = 300000
+
+	txn := testAPITransaction(testSignedTransaction(0, height))
+	if err := VerifyTransactionSignatures(txn, height); err != nil {
+		t.Fatalf("valid transaction rejected: %s", err)
+	}
+
+	txn.TransactionSignatures[0].Signature = strings.Repeat("00", crypto.SignatureSize)
+	if err := VerifyTransactionSignatures(txn, height); err == nil {
+		t.Fatal("expected invalid signature to be rejected")
+	}
+
+	txn = testAPITransaction(testSignedTransaction(height+1, height))
+	if err := VerifyTransactionSignatures(txn, height); err == nil {
+		t.Fatal("expected timelocked input to be rejected")
+	}
+}
+
+func TestVerifyTransactionSignaturesCoveredFields(t *testing.T) {
+	const height = 300000
+
+	tests := []struct {
+		name      string
+		noOutputs bool
+		cf        CoveredFields
+	}{
+		// previously panicked with index out of range in SigHash
+		{"out of range", true, CoveredFields{SiacoinOutputs: []uint64{5}}},
+		{"unsorted", false, CoveredFields{SiacoinInputs: []uint64{0}, MinerFees: []uint64{0, 0}}},
+		{"whole transaction with fields", false, CoveredFields{WholeTransaction: true, SiacoinOutputs: []uint64{0}}},
+		{"signature out of range", false, CoveredFields{TransactionSignatures: []uint64{1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txn := testAPITransaction(testSignedTransaction(0, height))
+			if tt.noOutputs {
+				txn.SiacoinOutputs = nil
+			}
+			txn.TransactionSignatures[0].CoveredFields = tt.cf
+
+			if err := VerifyTransactionSignatures(txn, height); err == nil {
+				t.Fatal("expected invalid covered fields to be rejected")
+			}
+		})
+	}
+}
+
+func TestVerifyTransactionSignaturesFrivolous(t *testing.T) {
+	const height = 300000
+
+	sk1, pk1 := crypto.GenerateKeyPair()
+	sk2, pk2 := crypto.GenerateKeyPair()
+	txn := types.Transaction{
+		SiacoinInputs: []types.SiacoinInput{{
+			ParentID: types.SiacoinOutputID{1},
+			UnlockConditions: types.UnlockConditions{
+				PublicKeys:         []types.SiaPublicKey{types.Ed25519PublicKey(pk1), types.Ed25519PublicKey(pk2)},
+				SignaturesRequired: 1,
+			},
+		}},
+		SiacoinOutputs: []types.SiacoinOutput{{
+			Value:      types.SiacoinPrecision,
+			UnlockHash: types.UnlockHash{2},
+		}},
+		TransactionSignatures: []types.TransactionSignature{
+			{ParentID: crypto.Hash{1}, PublicKeyIndex: 0, CoveredFields: types.FullCoveredFields},
+			{ParentID: crypto.Hash{1}, PublicKeyIndex: 1, CoveredFields: types.FullCoveredFields},
+		},
+	}
+
+	for i, sk := range []crypto.SecretKey{sk1, sk2} {
+		sig := crypto.SignHash(txn.SigHash(i, height), sk)
+		txn.TransactionSignatures[i].Signature = sig[:]
+	}
+
+	if err := txn.StandaloneValid(height); err == nil {
+		t.Fatal("expected consensus to reject the frivolous signature")
+	} else if err := VerifyTransactionSignatures(testAPITransaction(txn), height); err == nil {
+		t.Fatal("expected frivolous signature to be rejected")
+	}
+}
+
+func TestVerifyBlockRevisionUnverifiable(t *testing.T) {
+	b := Block{
+		Height: 300000,
+		Transactions: []Transaction{{
+			ContractRevisions: []StorageContract{{ID: ContractID{1}}},
+		}},
+	}
+
+	if err := VerifyBlock(b); !errors.Is(err, ErrRevisionUnverifiable) {
+		t.Fatalf("expected ErrRevisionUnverifiable, got %v", err)
+	}
+}
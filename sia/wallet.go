@@ -15,7 +15,7 @@ type (
 	}
 
 	apiFees struct {
-		Address string         `json:"address"`
+		Address UnlockHash     `json:"address"`
 		Fee     types.Currency `json:"fee"`
 	}
 
@@ -61,7 +61,7 @@ func (a *APIClient) GetTransactionFees() (min, max types.Currency, err error) {
 }
 
 // GetAPIFees gets the current transaction fee and payout address of the Sia Central API
-func (a *APIClient) GetAPIFees() (fee types.Currency, address string, err error) {
+func (a *APIClient) GetAPIFees() (fee types.Currency, address UnlockHash, err error) {
 	var resp getFeesResp
 
 	code, err := a.makeAPIRequest(http.MethodGet, "/wallet/fees", nil, &resp)
@@ -82,7 +82,7 @@ func (a *APIClient) GetAPIFees() (fee types.Currency, address string, err error)
 }
 
 // FindAddressBalance gets all unspent outputs and the last n transactions for a list of addresses
-func (a *APIClient) FindAddressBalance(limit, page int, addresses []string) (resp GetTransactionsResp, err error) {
+func (a *APIClient) FindAddressBalance(limit, page int, addresses []UnlockHash) (resp GetTransactionsResp, err error) {
 	if len(addresses) > 10000 {
 		err = errors.New("maximum of 10000 addresses")
 		return
@@ -105,7 +105,7 @@ func (a *APIClient) FindAddressBalance(limit, page int, addresses []string) (res
 }
 
 // FindUsedAddresses gets all addresses that have been seen in a transaction on the blockchain
-func (a *APIClient) FindUsedAddresses(addresses []string) (used []AddressUsage, err error) {
+func (a *APIClient) FindUsedAddresses(addresses []UnlockHash) (used []AddressUsage, err error) {
 	var resp getAddressesResp
 
 	if len(addresses) > 10000 {
@@ -132,8 +132,8 @@ func (a *APIClient) FindUsedAddresses(addresses []string) (used []AddressUsage,
 }
 
 // GetAddressBalance gets all unspent outputs and the last n transactions of an address
-func (a *APIClient) GetAddressBalance(limit, page int, address string) (resp GetTransactionsResp, err error) {
-	code, err := a.makeAPIRequest(http.MethodGet, fmt.Sprintf("/wallet/addresses/%s", address), nil, &resp)
+func (a *APIClient) GetAddressBalance(limit, page int, address UnlockHash) (resp GetTransactionsResp, err error) {
+	code, err := a.makeAPIRequest(http.MethodGet, fmt.Sprintf("/wallet/addresses/%s?limit=%d&page=%d", address, limit, page), nil, &resp)
 
 	if err != nil {
 		return
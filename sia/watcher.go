@@ -0,0 +1,228 @@
+package sia
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// ContractEventRevised is emitted when a contract's revision number
+	// increases
+	ContractEventRevised ContractEventType = "revised"
+	// ContractEventProofSubmitted is emitted when a storage proof for the
+	// contract is included in a block
+	ContractEventProofSubmitted ContractEventType = "proof_submitted"
+	// ContractEventProofConfirmed is emitted when a contract's storage proof
+	// is confirmed
+	ContractEventProofConfirmed ContractEventType = "proof_confirmed"
+	// ContractEventMissed is emitted when a contract's proof deadline passes
+	// without a storage proof
+	ContractEventMissed ContractEventType = "missed"
+	// ContractEventExpired is emitted when a contract's proof window starts
+	ContractEventExpired ContractEventType = "expired"
+	// ContractEventUnused is emitted when a contract is marked as unused or
+	// its proof deadline passes while unused
+	ContractEventUnused ContractEventType = "unused"
+
+	// DefaultWatchInterval is the refresh interval used by watchers created
+	// with an interval that is not positive
+	DefaultWatchInterval = time.Minute
+)
+
+type (
+	// ContractEventType is the type of change observed in a contract
+	ContractEventType string
+
+	// ContractEvent is a change in a watched contract's state. Old and New are
+	// the state of the contract before and after the change.
+	ContractEvent struct {
+		Type       ContractEventType `json:"type"`
+		ContractID ContractID        `json:"contract_id"`
+		Height     uint64            `json:"height"`
+		Old        StorageContract   `json:"old"`
+		New        StorageContract   `json:"new"`
+	}
+
+	// ContractWatcher periodically refreshes a set of contracts from the Sia
+	// Central explorer and emits events when their state changes
+	ContractWatcher struct {
+		client   *APIClient
+		interval time.Duration
+
+		mu        sync.Mutex
+		height    uint64
+		contracts map[ContractID]*StorageContract
+	}
+)
+
+// NewContractWatcher creates a new contract watcher that refreshes its
+// contracts every interval, or every DefaultWatchInterval if interval is not
+// positive
+func NewContractWatcher(client *APIClient, interval time.Duration) *ContractWatcher {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	return &ContractWatcher{
+		client:    client,
+		interval:  interval,
+		contracts: make(map[ContractID]*StorageContract),
+	}
+}
+
+// Add adds contracts to the watcher. The first time a contract is refreshed its
+// state is recorded without emitting any events.
+func (cw *ContractWatcher) Add(ids ...ContractID) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	for _, id := range ids {
+		if _, exists := cw.contracts[id]; !exists {
+			cw.contracts[id] = nil
+		}
+	}
+}
+
+// Remove removes contracts from the watcher
+func (cw *ContractWatcher) Remove(ids ...ContractID) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	for _, id := range ids {
+		delete(cw.contracts, id)
+	}
+}
+
+// Contract returns the last known state of a watched contract
+func (cw *ContractWatcher) Contract(id ContractID) (StorageContract, bool) {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	c, exists := cw.contracts[id]
+	if !exists || c == nil {
+		return StorageContract{}, false
+	}
+
+	return *c, true
+}
+
+// contractEvents compares the previous and current state of a contract.
+// lastHeight is the chain height of the previous refresh and height is the
+// current chain height.
+func contractEvents(prev, current StorageContract, lastHeight, height uint64) (events []ContractEvent) {
+	add := func(t ContractEventType) {
+		events = append(events, ContractEvent{
+			Type:       t,
+			ContractID: current.ID,
+			Height:     height,
+			Old:        prev,
+			New:        current,
+		})
+	}
+
+	if current.RevisionNumber > prev.RevisionNumber {
+		add(ContractEventRevised)
+	}
+
+	if prev.ProofHeight == 0 && current.ProofHeight != 0 {
+		add(ContractEventProofSubmitted)
+	}
+
+	if !prev.ProofConfirmed && current.ProofConfirmed {
+		add(ContractEventProofConfirmed)
+	}
+
+	if lastHeight < current.ExpirationHeight && height >= current.ExpirationHeight {
+		add(ContractEventExpired)
+	}
+
+	deadlinePassed := lastHeight <= current.ProofDeadline && height > current.ProofDeadline
+	switch {
+	case current.Unused && (!prev.Unused || deadlinePassed):
+		add(ContractEventUnused)
+	case deadlinePassed && current.ProofHeight == 0:
+		add(ContractEventMissed)
+	}
+
+	return
+}
+
+// Refresh fetches the current state of all watched contracts and returns the
+// events for any changes since the last refresh
+func (cw *ContractWatcher) Refresh() (events []ContractEvent, err error) {
+	index, err := cw.client.GetChainIndex()
+	if err != nil {
+		return
+	}
+
+	cw.mu.Lock()
+	ids := make([]ContractID, 0, len(cw.contracts))
+	for id := range cw.contracts {
+		ids = append(ids, id)
+	}
+	cw.mu.Unlock()
+
+	var contracts []StorageContract
+	err = batches(len(ids), maxBatchSize, func(start, end int) error {
+		batch, err := cw.client.FindContractsByID(ids[start:end]...)
+		contracts = append(contracts, batch...)
+		return err
+	})
+	if err != nil {
+		return
+	}
+
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	for i := range contracts {
+		c := contracts[i]
+
+		old, exists := cw.contracts[c.ID]
+		if !exists {
+			// removed while refreshing
+			continue
+		} else if old != nil {
+			events = append(events, contractEvents(*old, c, cw.height, index.Height)...)
+		}
+
+		cw.contracts[c.ID] = &c
+	}
+
+	cw.height = index.Height
+	return
+}
+
+// watch calls refresh immediately and then every interval until the context
+// is cancelled or refresh fails
+func watch(ctx context.Context, interval time.Duration, refresh func() error) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		if err := refresh(); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Watch refreshes the watched contracts every interval, calling fn for each
+// event, until the context is cancelled or a refresh fails. The state of the
+// watcher is kept, so Watch can be called again after an error without losing
+// events.
+func (cw *ContractWatcher) Watch(ctx context.Context, fn func(ContractEvent)) error {
+	return watch(ctx, cw.interval, func() error {
+		events, err := cw.Refresh()
+		for _, e := range events {
+			fn(e)
+		}
+		return err
+	})
+}
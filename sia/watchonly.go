@@ -0,0 +1,453 @@
+package sia
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.sia.tech/siad/types"
+)
+
+const (
+	// WalletEventReceived siacoins were received by a watched address
+	WalletEventReceived WalletEventType = "received"
+	// WalletEventSent siacoins were sent from a watched address
+	WalletEventSent WalletEventType = "sent"
+)
+
+type (
+	// WalletEventType is the direction of a payment
+	WalletEventType string
+
+	// WalletEvent is a payment to or from the watched addresses. Each payment
+	// emits an event when it is first seen unconfirmed and again when it is
+	// confirmed. Value is the amount received, or the amount that left the
+	// wallet including fees.
+	WalletEvent struct {
+		Type        WalletEventType    `json:"type"`
+		Confirmed   bool               `json:"confirmed"`
+		Height      uint64             `json:"height"`
+		Value       types.Currency     `json:"value"`
+		Summary     TransactionSummary `json:"summary"`
+		Transaction Transaction        `json:"transaction"`
+	}
+
+	// WalletBalance the balance of the watched addresses. Confirmed includes
+	// only mature outputs; Spendable excludes outputs spent by unconfirmed
+	// transactions.
+	WalletBalance struct {
+		Height              uint64         `json:"height"`
+		Confirmed           types.Currency `json:"confirmed"`
+		Spendable           types.Currency `json:"spendable"`
+		Immature            types.Currency `json:"immature"`
+		UnconfirmedIncoming types.Currency `json:"unconfirmed_incoming"`
+		UnconfirmedOutgoing types.Currency `json:"unconfirmed_outgoing"`
+		Siafunds            types.Currency `json:"siafunds"`
+		SiafundClaim        types.Currency `json:"siafund_claim"`
+	}
+
+	// WatchOnlyWallet tracks the unspent outputs and transaction history of a
+	// set of addresses using the Sia Central API. It holds no keys.
+	WatchOnlyWallet struct {
+		client   *APIClient
+		interval time.Duration
+
+		mu        sync.Mutex
+		addresses map[UnlockHash]bool
+		// pending are addresses whose history has not been retrieved yet.
+		// Transactions that only involve pending addresses do not emit
+		// events.
+		pending map[UnlockHash]bool
+		height  uint64
+
+		siacoinOutputs map[OutputID]SiacoinOutput
+		siafundOutputs map[OutputID]SiafundOutput
+		siafundClaim   types.Currency
+		transactions   map[TransactionID]Transaction
+		unconfirmed    map[TransactionID]Transaction
+	}
+)
+
+// NewWatchOnlyWallet creates a new watch-only wallet tracking the addresses
+// that refreshes every interval, or every DefaultWatchInterval if interval is
+// not positive
+func NewWatchOnlyWallet(client *APIClient, interval time.Duration, addresses ...UnlockHash) *WatchOnlyWallet {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	w := &WatchOnlyWallet{
+		client:         client,
+		interval:       interval,
+		addresses:      make(map[UnlockHash]bool),
+		pending:        make(map[UnlockHash]bool),
+		siacoinOutputs: make(map[OutputID]SiacoinOutput),
+		siafundOutputs: make(map[OutputID]SiafundOutput),
+		transactions:   make(map[TransactionID]Transaction),
+		unconfirmed:    make(map[TransactionID]Transaction),
+	}
+	w.AddAddresses(addresses...)
+	return w
+}
+
+// AddAddresses adds addresses to the wallet. The history of new addresses is
+// retrieved on the next refresh without emitting events for it.
+func (w *WatchOnlyWallet) AddAddresses(addresses ...UnlockHash) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, addr := range addresses {
+		if !w.addresses[addr] {
+			w.addresses[addr] = true
+			w.pending[addr] = true
+		}
+	}
+}
+
+// Addresses returns the watched addresses
+func (w *WatchOnlyWallet) Addresses() (addresses []UnlockHash) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for addr := range w.addresses {
+		addresses = append(addresses, addr)
+	}
+
+	sortUnlockHashes(addresses)
+	return
+}
+
+// ownsOutput returns true if the output is sent to a watched address
+func (w *WatchOnlyWallet) ownsOutput(o SiacoinOutput) bool {
+	return w.addresses[o.UnlockHash]
+}
+
+// spentUnconfirmed returns the ids of outputs spent by unconfirmed
+// transactions
+func (w *WatchOnlyWallet) spentUnconfirmed() map[OutputID]bool {
+	spent := make(map[OutputID]bool)
+	for _, t := range w.unconfirmed {
+		for _, sci := range t.SiacoinInputs {
+			spent[sci.OutputID] = true
+		}
+	}
+	return spent
+}
+
+// Balance returns the current balance of the watched addresses
+func (w *WatchOnlyWallet) Balance() (b WalletBalance) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	b.Height = w.height
+	b.SiafundClaim = w.siafundClaim
+
+	spent := w.spentUnconfirmed()
+	for _, o := range w.siacoinOutputs {
+		if o.MaturityHeight > w.height {
+			b.Immature = b.Immature.Add(o.Value)
+			continue
+		}
+
+		b.Confirmed = b.Confirmed.Add(o.Value)
+		if !spent[o.OutputID] {
+			b.Spendable = b.Spendable.Add(o.Value)
+		}
+	}
+
+	for _, o := range w.siafundOutputs {
+		b.Siafunds = b.Siafunds.Add(o.Value)
+	}
+
+	for _, t := range w.unconfirmed {
+		for _, sci := range t.SiacoinInputs {
+			if w.ownsOutput(sci.SiacoinOutput) {
+				b.UnconfirmedOutgoing = b.UnconfirmedOutgoing.Add(sci.Value)
+			}
+		}
+
+		for _, sco := range t.SiacoinOutputs {
+			if w.ownsOutput(sco) {
+				b.UnconfirmedIncoming = b.UnconfirmedIncoming.Add(sco.Value)
+			}
+		}
+	}
+
+	return
+}
+
+// UnspentOutputs returns every confirmed unspent siacoin output of the
+// watched addresses, including immature outputs
+func (w *WatchOnlyWallet) UnspentOutputs() (outputs []SiacoinOutput) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, o := range w.siacoinOutputs {
+		outputs = append(outputs, o)
+	}
+
+	sortOutputs(outputs)
+	return
+}
+
+// SpendableOutputs returns the unspent siacoin outputs that are mature and not
+// spent by an unconfirmed transaction
+func (w *WatchOnlyWallet) SpendableOutputs() (outputs []SiacoinOutput) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	spent := w.spentUnconfirmed()
+	for _, o := range w.siacoinOutputs {
+		if o.MaturityHeight > w.height || spent[o.OutputID] {
+			continue
+		}
+		outputs = append(outputs, o)
+	}
+
+	sortOutputs(outputs)
+	return
+}
+
+// Transactions returns the confirmed transaction history of the watched
+// addresses, most recent first
+func (w *WatchOnlyWallet) Transactions() (transactions []Transaction) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, t := range w.transactions {
+		transactions = append(transactions, t)
+	}
+
+	sort.Slice(transactions, func(i, j int) bool {
+		if transactions[i].BlockHeight != transactions[j].BlockHeight {
+			return transactions[i].BlockHeight > transactions[j].BlockHeight
+		}
+		return transactions[i].ID.String() < transactions[j].ID.String()
+	})
+	return
+}
+
+// UnconfirmedTransactions returns the unconfirmed transactions of the watched
+// addresses
+func (w *WatchOnlyWallet) UnconfirmedTransactions() (transactions []Transaction) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, t := range w.unconfirmed {
+		transactions = append(transactions, t)
+	}
+
+	sort.Slice(transactions, func(i, j int) bool {
+		return transactions[i].ID.String() < transactions[j].ID.String()
+	})
+	return
+}
+
+// sortOutputs sorts outputs by height and id
+func sortOutputs(outputs []SiacoinOutput) {
+	sort.Slice(outputs, func(i, j int) bool {
+		if outputs[i].BlockHeight != outputs[j].BlockHeight {
+			return outputs[i].BlockHeight < outputs[j].BlockHeight
+		}
+		return outputs[i].OutputID.String() < outputs[j].OutputID.String()
+	})
+}
+
+// paymentEvent returns the event for a transaction involving the addresses.
+// false is returned if the transaction does not move the addresses' siacoins.
+func paymentEvent(t Transaction, confirmed bool, addresses []UnlockHash) (WalletEvent, bool) {
+	s := ExplainTransaction(t, addresses...)
+	if s.OwnInputs.IsZero() && s.OwnOutputs.IsZero() {
+		return WalletEvent{}, false
+	}
+
+	e := WalletEvent{
+		Type:        WalletEventReceived,
+		Confirmed:   confirmed,
+		Height:      t.BlockHeight,
+		Value:       s.OwnOutputs,
+		Summary:     s,
+		Transaction: t,
+	}
+
+	if !s.OwnInputs.IsZero() {
+		e.Type = WalletEventSent
+		e.Value = types.ZeroCurrency
+		if s.OwnInputs.Cmp(s.OwnOutputs) > 0 {
+			e.Value = s.OwnInputs.Sub(s.OwnOutputs)
+		}
+	}
+
+	return e, true
+}
+
+// onlyPending returns true if every watched address the transaction involves
+// is pending, meaning the transaction is part of the initial history of newly
+// added addresses
+func (w *WatchOnlyWallet) onlyPending(t Transaction, pending map[UnlockHash]bool) bool {
+	var involved []UnlockHash
+	for _, sci := range t.SiacoinInputs {
+		involved = append(involved, sci.UnlockHash)
+	}
+	for _, sco := range t.SiacoinOutputs {
+		involved = append(involved, sco.UnlockHash)
+	}
+	for _, sfi := range t.SiafundInputs {
+		involved = append(involved, sfi.UnlockHash)
+	}
+	for _, sfo := range t.SiafundOutputs {
+		involved = append(involved, sfo.UnlockHash)
+	}
+
+	for _, addr := range involved {
+		if w.addresses[addr] && !pending[addr] {
+			return false
+		}
+	}
+	return true
+}
+
+// Refresh retrieves the unspent outputs, unconfirmed transactions and new
+// confirmed transactions of the watched addresses and returns payment events
+// since the last refresh. Every transaction not seen before emits an event,
+// except the history of newly added addresses, which is recorded without
+// emitting events. On the first refresh every address is new. Transaction
+// history is assumed to be returned most recent first, so paging stops at the
+// first transaction that is already known, except for new addresses whose full
+// history is retrieved.
+func (w *WatchOnlyWallet) Refresh() (events []WalletEvent, err error) {
+	index, err := w.client.GetChainIndex()
+	if err != nil {
+		return
+	}
+
+	w.mu.Lock()
+	addresses := make([]UnlockHash, 0, len(w.addresses))
+	var synced, fresh []UnlockHash
+	for addr := range w.addresses {
+		addresses = append(addresses, addr)
+		if w.pending[addr] {
+			fresh = append(fresh, addr)
+		} else {
+			synced = append(synced, addr)
+		}
+	}
+	pending := make(map[UnlockHash]bool, len(w.pending))
+	for addr := range w.pending {
+		pending[addr] = true
+	}
+	w.mu.Unlock()
+
+	var (
+		siacoinOutputs []SiacoinOutput
+		siafundOutputs []SiafundOutput
+		unconfirmed    []Transaction
+		confirmed      []Transaction
+		claim          types.Currency
+	)
+
+	err = batches(len(addresses), maxBatchSize, func(start, end int) error {
+		resp, err := w.client.FindAddressBalance(1, 0, addresses[start:end])
+		if err != nil {
+			return err
+		}
+
+		siacoinOutputs = append(siacoinOutputs, resp.UnspentSiacoinOutputs...)
+		siafundOutputs = append(siafundOutputs, resp.UnspentSiafundOutputs...)
+		unconfirmed = append(unconfirmed, resp.UnconfirmedTransactions...)
+		claim = claim.Add(resp.SiafundClaim)
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	// history retrieves the confirmed transactions of the addresses. Unless
+	// full is set, paging stops at the first known transaction.
+	history := func(addrs []UnlockHash, full bool) error {
+		return batches(len(addrs), maxBatchSize, func(start, end int) error {
+			it := w.client.AddressSetHistory(addrs[start:end], 0)
+			for it.Next() {
+				t := it.Transaction()
+
+				w.mu.Lock()
+				_, known := w.transactions[t.ID]
+				w.mu.Unlock()
+
+				if known && !full {
+					break
+				}
+				confirmed = append(confirmed, t)
+			}
+
+			return it.Err()
+		})
+	}
+
+	if err = history(synced, false); err != nil {
+		return
+	} else if err = history(fresh, true); err != nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, t := range confirmed {
+		if _, exists := w.transactions[t.ID]; exists {
+			continue
+		}
+		w.transactions[t.ID] = t
+
+		if w.onlyPending(t, pending) {
+			continue
+		} else if e, ok := paymentEvent(t, true, addresses); ok {
+			events = append(events, e)
+		}
+	}
+
+	current := make(map[TransactionID]Transaction, len(unconfirmed))
+	for _, t := range unconfirmed {
+		current[t.ID] = t
+
+		_, seen := w.unconfirmed[t.ID]
+		_, mined := w.transactions[t.ID]
+		if seen || mined || w.onlyPending(t, pending) {
+			continue
+		} else if e, ok := paymentEvent(t, false, addresses); ok {
+			events = append(events, e)
+		}
+	}
+	w.unconfirmed = current
+
+	w.siacoinOutputs = make(map[OutputID]SiacoinOutput, len(siacoinOutputs))
+	for _, o := range siacoinOutputs {
+		w.siacoinOutputs[o.OutputID] = o
+	}
+
+	w.siafundOutputs = make(map[OutputID]SiafundOutput, len(siafundOutputs))
+	for _, o := range siafundOutputs {
+		w.siafundOutputs[o.OutputID] = o
+	}
+
+	w.siafundClaim = claim
+	w.height = index.Height
+	// addresses added during the refresh are still pending
+	for addr := range pending {
+		delete(w.pending, addr)
+	}
+	return
+}
+
+// Watch refreshes the wallet every interval, calling fn for each event, until
+// the context is cancelled or a refresh fails
+func (w *WatchOnlyWallet) Watch(ctx context.Context, fn func(WalletEvent)) error {
+	return watch(ctx, w.interval, func() error {
+		events, err := w.Refresh()
+		for _, e := range events {
+			fn(e)
+		}
+		return err
+	})
+}